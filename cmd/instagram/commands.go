@@ -0,0 +1,349 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"strings"
+
+	"github.com/gedex/go-instagram/instagram"
+)
+
+// A command is a subcommand of the tool, such as "user get".
+type command struct {
+	name string
+	args string
+	help string
+	run  func(ctx context.Context, e *env, args []string) error
+}
+
+func (c *command) usageError() error {
+	return &usageError{"instagram [flags] " + c.name + " " + c.args}
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{"user get", "[user-id]", "show a user, self by default", userGet},
+		{"user search", "<query>", "search users by name", userSearch},
+		{"user feed", "", "list the authenticated user's feed", userFeed},
+		{"user recent", "[user-id]", "list the recent media of a user, self by default", userRecent},
+		{"user liked", "", "list the media liked by the authenticated user", userLiked},
+
+		{"media get", "<media-id|permalink>", "show a media", mediaGet},
+		{"media search", "-lat <lat> -lng <lng> [-distance <m>]", "search media by location", mediaSearch},
+		{"media popular", "", "list popular media", mediaPopular},
+
+		{"tag get", "<tag>", "show a tag", tagGet},
+		{"tag search", "<query>", "search tags by name", tagSearch},
+		{"tag recent", "<tag>", "list the recent media of a tag", tagRecent},
+
+		{"location get", "<location-id>", "show a location", locationGet},
+		{"location search", "-lat <lat> -lng <lng> [-distance <m>]", "search locations", locationSearch},
+		{"location recent", "<location-id>", "list the recent media of a location", locationRecent},
+
+		{"follows", "[user-id]", "list the users a user follows, self by default", follows},
+		{"followed-by", "[user-id]", "list the followers of a user, self by default", followedBy},
+		{"requested-by", "", "list the users who requested to follow the authenticated user", requestedBy},
+
+		{"like", "<media-id>", "like a media", like},
+		{"unlike", "<media-id>", "remove a like from a media", unlike},
+
+		{"comment add", "<media-id> <text>...", "comment on a media", commentAdd},
+		{"comment delete", "<media-id> <comment-id>", "delete a comment", commentDelete},
+
+		{"relationship get", "<user-id>", "show the relationship with a user", relationship((*instagram.RelationshipsService).RelationshipContext)},
+		{"relationship follow", "<user-id>", "follow a user", relationship((*instagram.RelationshipsService).FollowContext)},
+		{"relationship unfollow", "<user-id>", "unfollow a user", relationship((*instagram.RelationshipsService).UnfollowContext)},
+		{"relationship block", "<user-id>", "block a user", relationship((*instagram.RelationshipsService).BlockContext)},
+		{"relationship unblock", "<user-id>", "unblock a user", relationship((*instagram.RelationshipsService).UnblockContext)},
+	}
+}
+
+// find returns the command with the given name.
+func find(name string) *command {
+	for _, c := range commands {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// nargs checks that args holds between min and max arguments of command
+// name. A negative max allows any number.
+func nargs(name string, args []string, min, max int) error {
+	if len(args) < min || (max >= 0 && len(args) > max) {
+		return find(name).usageError()
+	}
+	return nil
+}
+
+// optional returns the first of args, or "" if there is none.
+func optional(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
+
+// list prints the items of it, up to the env's limit or a single page.
+func list[T any](e *env, it *instagram.Iterator[T]) error {
+	if e.limit > 0 {
+		it.MaxItems = e.limit
+	} else {
+		it.MaxPages = 1
+	}
+	var items []T
+	for it.Next() {
+		items = append(items, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	return e.out.print(items)
+}
+
+func userGet(ctx context.Context, e *env, args []string) error {
+	if err := nargs("user get", args, 0, 1); err != nil {
+		return err
+	}
+	user, err := e.client.Users.GetContext(ctx, optional(args))
+	if err != nil {
+		return err
+	}
+	return e.out.print(user)
+}
+
+func userSearch(ctx context.Context, e *env, args []string) error {
+	if err := nargs("user search", args, 1, -1); err != nil {
+		return err
+	}
+	opt := &instagram.UserSearchOptions{Count: e.limit}
+	users, _, err := e.client.Users.SearchContext(ctx, strings.Join(args, " "), opt)
+	if err != nil {
+		return err
+	}
+	return e.out.print(users)
+}
+
+func userFeed(ctx context.Context, e *env, args []string) error {
+	if err := nargs("user feed", args, 0, 0); err != nil {
+		return err
+	}
+	return list(e, e.client.Users.MediaFeedIterator(ctx, nil))
+}
+
+func userRecent(ctx context.Context, e *env, args []string) error {
+	if err := nargs("user recent", args, 0, 1); err != nil {
+		return err
+	}
+	return list(e, e.client.Users.RecentMediaIterator(ctx, optional(args), nil))
+}
+
+func userLiked(ctx context.Context, e *env, args []string) error {
+	if err := nargs("user liked", args, 0, 0); err != nil {
+		return err
+	}
+	return list(e, e.client.Users.LikedMediaIterator(ctx, nil))
+}
+
+func mediaGet(ctx context.Context, e *env, args []string) error {
+	if err := nargs("media get", args, 1, 1); err != nil {
+		return err
+	}
+	var media *instagram.Media
+	var err error
+	if strings.Contains(args[0], "/") {
+		var code string
+		if code, err = instagram.ParseShortcode(args[0]); err != nil {
+			return err
+		}
+		media, err = e.client.Media.ShortcodeContext(ctx, code)
+	} else {
+		media, err = e.client.Media.GetContext(ctx, args[0])
+	}
+	if err != nil {
+		return err
+	}
+	return e.out.print(media)
+}
+
+// geoFlags parses the -lat, -lng and -distance flags of the search commands.
+func geoFlags(name string, e *env, args []string) (lat, lng, distance float64, err error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Float64Var(&lat, "lat", 0, "latitude of the center of the search area")
+	fs.Float64Var(&lng, "lng", 0, "longitude of the center of the search area")
+	fs.Float64Var(&distance, "distance", 0, "search radius in meters, up to 5000")
+	if err := fs.Parse(args); err != nil {
+		return 0, 0, 0, err
+	}
+	if fs.NArg() != 0 || !isSet(fs, "lat") || !isSet(fs, "lng") {
+		return 0, 0, 0, find(name).usageError()
+	}
+	return lat, lng, distance, nil
+}
+
+func isSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func mediaSearch(ctx context.Context, e *env, args []string) error {
+	lat, lng, distance, err := geoFlags("media search", e, args)
+	if err != nil {
+		return err
+	}
+	opt := &instagram.MediaSearchOptions{Distance: distance}
+	media, _, err := e.client.Media.SearchContext(ctx, lat, lng, opt)
+	if err != nil {
+		return err
+	}
+	return e.out.print(media)
+}
+
+func mediaPopular(ctx context.Context, e *env, args []string) error {
+	if err := nargs("media popular", args, 0, 0); err != nil {
+		return err
+	}
+	media, _, err := e.client.Media.PopularContext(ctx)
+	if err != nil {
+		return err
+	}
+	return e.out.print(media)
+}
+
+func tagGet(ctx context.Context, e *env, args []string) error {
+	if err := nargs("tag get", args, 1, 1); err != nil {
+		return err
+	}
+	tag, err := e.client.Tags.GetContext(ctx, strings.TrimPrefix(args[0], "#"))
+	if err != nil {
+		return err
+	}
+	return e.out.print(tag)
+}
+
+func tagSearch(ctx context.Context, e *env, args []string) error {
+	if err := nargs("tag search", args, 1, 1); err != nil {
+		return err
+	}
+	tags, _, err := e.client.Tags.SearchContext(ctx, strings.TrimPrefix(args[0], "#"))
+	if err != nil {
+		return err
+	}
+	return e.out.print(tags)
+}
+
+func tagRecent(ctx context.Context, e *env, args []string) error {
+	if err := nargs("tag recent", args, 1, 1); err != nil {
+		return err
+	}
+	return list(e, e.client.Tags.RecentMediaIterator(ctx, strings.TrimPrefix(args[0], "#"), nil))
+}
+
+func locationGet(ctx context.Context, e *env, args []string) error {
+	if err := nargs("location get", args, 1, 1); err != nil {
+		return err
+	}
+	loc, err := e.client.Locations.GetContext(ctx, instagram.LocationID(args[0]))
+	if err != nil {
+		return err
+	}
+	return e.out.print(loc)
+}
+
+func locationSearch(ctx context.Context, e *env, args []string) error {
+	lat, lng, distance, err := geoFlags("location search", e, args)
+	if err != nil {
+		return err
+	}
+	opt := &instagram.LocationSearchOptions{Distance: distance}
+	locs, err := e.client.Locations.SearchContext(ctx, lat, lng, opt)
+	if err != nil {
+		return err
+	}
+	return e.out.print(locs)
+}
+
+func locationRecent(ctx context.Context, e *env, args []string) error {
+	if err := nargs("location recent", args, 1, 1); err != nil {
+		return err
+	}
+	return list(e, e.client.Locations.RecentMediaIterator(ctx, instagram.LocationID(args[0]), nil))
+}
+
+func follows(ctx context.Context, e *env, args []string) error {
+	if err := nargs("follows", args, 0, 1); err != nil {
+		return err
+	}
+	return list(e, e.client.Relationships.FollowsIterator(ctx, optional(args), nil))
+}
+
+func followedBy(ctx context.Context, e *env, args []string) error {
+	if err := nargs("followed-by", args, 0, 1); err != nil {
+		return err
+	}
+	return list(e, e.client.Relationships.FollowedByIterator(ctx, optional(args), nil))
+}
+
+func requestedBy(ctx context.Context, e *env, args []string) error {
+	if err := nargs("requested-by", args, 0, 0); err != nil {
+		return err
+	}
+	return list(e, e.client.Relationships.RequestedByIterator(ctx, nil))
+}
+
+func like(ctx context.Context, e *env, args []string) error {
+	if err := nargs("like", args, 1, 1); err != nil {
+		return err
+	}
+	return e.client.Likes.LikeContext(ctx, args[0])
+}
+
+func unlike(ctx context.Context, e *env, args []string) error {
+	if err := nargs("unlike", args, 1, 1); err != nil {
+		return err
+	}
+	return e.client.Likes.UnlikeContext(ctx, args[0])
+}
+
+func commentAdd(ctx context.Context, e *env, args []string) error {
+	if err := nargs("comment add", args, 2, -1); err != nil {
+		return err
+	}
+	return e.client.Comments.AddContext(ctx, args[0], []string{strings.Join(args[1:], " ")})
+}
+
+func commentDelete(ctx context.Context, e *env, args []string) error {
+	if err := nargs("comment delete", args, 2, 2); err != nil {
+		return err
+	}
+	return e.client.Comments.DeleteContext(ctx, args[0], args[1])
+}
+
+// relationship returns the run function of a relationship command calling
+// method.
+func relationship(method func(*instagram.RelationshipsService, context.Context, string) (*instagram.Relationship, error)) func(context.Context, *env, []string) error {
+	return func(ctx context.Context, e *env, args []string) error {
+		if len(args) != 1 {
+			return &usageError{"instagram [flags] relationship get|follow|unfollow|block|unblock <user-id>"}
+		}
+		rel, err := method(e.client.Relationships, ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return e.out.print(rel)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Command instagram is a command-line client for the Instagram API.
+
+Usage:
+
+	instagram [flags] <command> [subcommand] [args]
+
+Run instagram -help for the list of commands.
+
+The access token and application credentials are taken, in order of
+precedence, from the -token, -client-id and -client-secret flags, the
+INSTAGRAM_ACCESS_TOKEN, INSTAGRAM_CLIENT_ID and INSTAGRAM_CLIENT_SECRET
+environment variables, and the JSON config file given by -config, which
+defaults to instagram/config.json in the user's config directory:
+
+	{
+		"access_token": "...",
+		"client_id": "...",
+		"client_secret": "..."
+	}
+
+Results are printed as an aligned table, or with -format json or
+-format ndjson as a JSON document or one JSON object per line. List commands
+print the first page of results unless -limit asks for more.
+*/
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gedex/go-instagram/instagram"
+)
+
+// config holds the credentials read from the config file.
+type config struct {
+	AccessToken  string `json:"access_token"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+// env is what commands run with.
+type env struct {
+	client *instagram.Client
+	out    *printer
+	stderr io.Writer
+
+	// limit is the maximum number of items printed by list commands. Zero
+	// prints the first page.
+	limit int
+}
+
+// usageError reports that a command was called with wrong arguments.
+type usageError struct {
+	usage string
+}
+
+func (e *usageError) Error() string {
+	return "usage: " + e.usage
+}
+
+func main() {
+	err := run(context.Background(), os.Args[1:], os.Stdout, os.Stderr, os.Getenv)
+	if err == nil || err == flag.ErrHelp {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "instagram: %v\n", err)
+	var uerr *usageError
+	if errors.As(err, &uerr) {
+		os.Exit(2)
+	}
+	os.Exit(1)
+}
+
+// run runs the command line args, printing results to stdout.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer, getenv func(string) string) error {
+	fs := flag.NewFlagSet("instagram", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		token        = fs.String("token", "", "access token")
+		clientID     = fs.String("client-id", "", "application client_id")
+		clientSecret = fs.String("client-secret", "", "application client_secret")
+		configPath   = fs.String("config", "", "config file (default instagram/config.json in the user config directory)")
+		format       = fs.String("format", "table", "output format: table, json or ndjson")
+		limit        = fs.Int("limit", 0, "maximum number of items listed, following pagination (default first page)")
+		timeout      = fs.Duration("timeout", 30*time.Second, "timeout of the whole command")
+		baseURL      = fs.String("base-url", instagram.BaseURL, "API base URL")
+	)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: instagram [flags] <command> [subcommand] [args]\n\nCommands:\n")
+		for _, c := range commands {
+			fmt.Fprintf(stderr, "  %-40s %s\n", c.name+" "+c.args, c.help)
+		}
+		fmt.Fprintf(stderr, "\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	out, err := newPrinter(stdout, *format)
+	if err != nil {
+		return err
+	}
+	if *limit < 0 {
+		return fmt.Errorf("invalid -limit %d", *limit)
+	}
+
+	conf, err := loadConfig(*configPath, getenv)
+	if err != nil {
+		return err
+	}
+	client := instagram.NewClient(nil)
+	client.AccessToken = firstNonEmpty(*token, getenv("INSTAGRAM_ACCESS_TOKEN"), conf.AccessToken)
+	client.ClientID = firstNonEmpty(*clientID, getenv("INSTAGRAM_CLIENT_ID"), conf.ClientID)
+	client.ClientSecret = firstNonEmpty(*clientSecret, getenv("INSTAGRAM_CLIENT_SECRET"), conf.ClientSecret)
+	if client.BaseURL, err = url.Parse(*baseURL); err != nil {
+		return fmt.Errorf("invalid -base-url: %v", err)
+	}
+
+	cmd, cmdArgs := lookup(fs.Args())
+	if cmd == nil {
+		fs.Usage()
+		return &usageError{"instagram [flags] <command> [subcommand] [args]"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+	e := &env{client: client, out: out, stderr: stderr, limit: *limit}
+	return cmd.run(ctx, e, cmdArgs)
+}
+
+// loadConfig reads the config file at path. When path is empty, the file
+// named by INSTAGRAM_CONFIG or the default one is read if it exists.
+func loadConfig(path string, getenv func(string) string) (*config, error) {
+	if path == "" {
+		path = getenv("INSTAGRAM_CONFIG")
+	}
+	explicit := path != ""
+	if !explicit {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return new(config), nil
+		}
+		path = filepath.Join(dir, "instagram", "config.json")
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && !explicit {
+		return new(config), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	conf := new(config)
+	if err := json.Unmarshal(data, conf); err != nil {
+		return nil, fmt.Errorf("config %v: %v", path, err)
+	}
+	return conf, nil
+}
+
+// lookup returns the command named by the first one or two args, and the
+// remaining args.
+func lookup(args []string) (*command, []string) {
+	if len(args) >= 2 {
+		name := args[0] + " " + args[1]
+		for _, c := range commands {
+			if c.name == name {
+				return c, args[2:]
+			}
+		}
+	}
+	if len(args) >= 1 {
+		for _, c := range commands {
+			if c.name == args[0] {
+				return c, args[1:]
+			}
+		}
+	}
+	return nil, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,165 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testRun runs args against a server using mux, with the environment in
+// vars, and returns what was printed.
+func testRun(t *testing.T, mux *http.ServeMux, vars map[string]string, args ...string) (string, error) {
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	if vars == nil {
+		vars = map[string]string{}
+	}
+	if _, ok := vars["INSTAGRAM_CONFIG"]; !ok {
+		vars["INSTAGRAM_CONFIG"] = filepath.Join(t.TempDir(), "none.json")
+		ioutil.WriteFile(vars["INSTAGRAM_CONFIG"], []byte("{}"), 0600)
+	}
+	var stdout, stderr bytes.Buffer
+	args = append([]string{"-base-url", server.URL + "/"}, args...)
+	err := run(context.Background(), args, &stdout, &stderr, func(k string) string { return vars[k] })
+	return stdout.String(), err
+}
+
+func TestRun_userGet(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/self", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("access_token"); got != "flag-token" {
+			t.Errorf("access_token = %q, want flag-token", got)
+		}
+		fmt.Fprint(w, `{"data":{"id":"3","username":"kevin","full_name":"Kevin S"}}`)
+	})
+
+	out, err := testRun(t, mux, map[string]string{"INSTAGRAM_ACCESS_TOKEN": "env-token"}, "-token", "flag-token", "user", "get")
+	if err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	want := "ID  USERNAME  FULL NAME\n3   kevin     Kevin S\n"
+	if out != want {
+		t.Errorf("run printed\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestRun_credentials(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config.json")
+	ioutil.WriteFile(config, []byte(`{"access_token":"config-token","client_id":"config-id"}`), 0600)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tags/go", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if got := q.Get("access_token"); got != "env-token" {
+			t.Errorf("access_token = %q, want env-token", got)
+		}
+		if got := q.Get("client_id"); got != "config-id" {
+			t.Errorf("client_id = %q, want config-id", got)
+		}
+		fmt.Fprint(w, `{"data":{"name":"go","media_count":7}}`)
+	})
+
+	vars := map[string]string{"INSTAGRAM_ACCESS_TOKEN": "env-token"}
+	out, err := testRun(t, mux, vars, "-config", config, "-format", "json", "tag", "get", "#go")
+	if err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if want := "{\n  \"media_count\": 7,\n  \"name\": \"go\"\n}\n"; out != want {
+		t.Errorf("run printed %q, want %q", out, want)
+	}
+}
+
+func TestRun_limit(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tags/go/media/recent", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			fmt.Fprintf(w, `{"pagination":{"next_url":"http://%v/tags/go/media/recent?page=2"},"data":[{"id":"1"},{"id":"2"}]}`, r.Host)
+			return
+		}
+		fmt.Fprint(w, `{"data":[{"id":"3"},{"id":"4"}]}`)
+	})
+
+	out, err := testRun(t, mux, nil, "-format", "ndjson", "-limit", "3", "tag", "recent", "go")
+	if err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if want := "{\"id\":\"1\"}\n{\"id\":\"2\"}\n{\"id\":\"3\"}\n"; out != want {
+		t.Errorf("run printed %q, want %q", out, want)
+	}
+
+	// Without -limit, only the first page is printed.
+	out, err = testRun(t, mux, nil, "-format", "ndjson", "tag", "recent", "go")
+	if err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if want := "{\"id\":\"1\"}\n{\"id\":\"2\"}\n"; out != want {
+		t.Errorf("run printed %q, want %q", out, want)
+	}
+}
+
+func TestRun_usage(t *testing.T) {
+	tests := [][]string{
+		{},
+		{"nope"},
+		{"media", "get"},
+		{"comment", "delete", "1"},
+		{"media", "search", "-lat", "1"},
+		{"relationship", "follow"},
+	}
+	for _, args := range tests {
+		_, err := testRun(t, http.NewServeMux(), nil, args...)
+		var uerr *usageError
+		if !errors.As(err, &uerr) {
+			t.Errorf("run(%q) returned error %v, want a usage error", args, err)
+		}
+	}
+
+	if _, err := testRun(t, http.NewServeMux(), nil, "-format", "xml", "media", "popular"); err == nil || !strings.Contains(err.Error(), "xml") {
+		t.Errorf("run with -format xml returned error %v, want unknown format", err)
+	}
+}
+
+func TestRun_commentAdd(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/media/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Request method = %v, want POST", r.Method)
+		}
+		if got := r.PostFormValue("text"); got != "nice shot" {
+			t.Errorf("text = %q, want %q", got, "nice shot")
+		}
+		fmt.Fprint(w, `{"meta":{"code":200}}`)
+	})
+
+	if _, err := testRun(t, mux, nil, "comment", "add", "1", "nice", "shot"); err != nil {
+		t.Errorf("run returned error: %v", err)
+	}
+}
+
+func TestRun_mediaSearch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/media/search", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("lat") != "0.0000000" || q.Get("lng") != "0.0000000" {
+			t.Errorf("lat, lng = %q, %q, want 0.0000000, 0.0000000", q.Get("lat"), q.Get("lng"))
+		}
+		fmt.Fprint(w, `{"data":[]}`)
+	})
+
+	if _, err := testRun(t, mux, nil, "media", "search", "-lat", "0", "-lng", "0"); err != nil {
+		t.Errorf("run returned error: %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gedex/go-instagram/instagram"
+)
+
+// printer writes command results in one of the output formats.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	switch format {
+	case "table", "json", "ndjson":
+		return &printer{w, format}, nil
+	}
+	return nil, fmt.Errorf("unknown output format %q, want table, json or ndjson", format)
+}
+
+// print writes v, a value or a slice of values returned by the library.
+func (p *printer) print(v interface{}) error {
+	switch p.format {
+	case "json":
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice && rv.IsNil() {
+			v = []interface{}{}
+		}
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(p.w, "%s\n", data)
+		return err
+	case "ndjson":
+		enc := json.NewEncoder(p.w)
+		for _, item := range items(v) {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	for i, item := range items(v) {
+		header, row := columns(item)
+		if i == 0 {
+			fmt.Fprintln(tw, strings.Join(header, "\t"))
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// items returns the elements of v if it's a slice, as pointers, or v alone.
+func items(v interface{}) []interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return []interface{}{v}
+	}
+	list := make([]interface{}, rv.Len())
+	for i := range list {
+		list[i] = rv.Index(i).Addr().Interface()
+	}
+	return list
+}
+
+// columns returns the table header and row of v.
+func columns(v interface{}) (header, row []string) {
+	switch v := v.(type) {
+	case *instagram.User:
+		return []string{"ID", "USERNAME", "FULL NAME"},
+			[]string{v.ID, v.Username, v.FullName}
+	case *instagram.Media:
+		var likes, comments int
+		if v.Likes != nil {
+			likes = v.Likes.Count
+		}
+		if v.Comments != nil {
+			comments = v.Comments.Count
+		}
+		return []string{"ID", "TYPE", "CREATED", "LIKES", "COMMENTS", "LINK"},
+			[]string{v.ID, string(v.Type), formatTime(v.CreatedTime), strconv.Itoa(likes), strconv.Itoa(comments), v.Link}
+	case *instagram.Tag:
+		return []string{"NAME", "MEDIA"},
+			[]string{v.Name, strconv.Itoa(v.MediaCount)}
+	case *instagram.Location:
+		return []string{"ID", "NAME", "LATITUDE", "LONGITUDE"},
+			[]string{string(v.ID), v.Name, formatFloat(v.Latitude), formatFloat(v.Longitude)}
+	case *instagram.Relationship:
+		return []string{"OUTGOING", "INCOMING"},
+			[]string{v.OutgoingStatus, v.IncomingStatus}
+	case *instagram.Comment:
+		var from string
+		if v.From != nil {
+			from = v.From.Username
+		}
+		return []string{"ID", "FROM", "CREATED", "TEXT"},
+			[]string{v.ID, from, formatTime(v.CreatedTime), v.Text}
+	}
+	return []string{"VALUE"}, []string{fmt.Sprint(v)}
+}
+
+func formatTime(t instagram.Timestamp) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
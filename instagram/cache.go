@@ -0,0 +1,306 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A Cache stores API responses for Client.Do. Implementations must be safe
+// for concurrent use. Errors are not reported: a Cache that fails to store an
+// entry simply misses on the next lookup.
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, e *CacheEntry)
+	Delete(key string)
+}
+
+// CacheEntry is a cached response.
+type CacheEntry struct {
+	Header http.Header
+	Body   []byte
+
+	// Stored is when the response was received or last revalidated.
+	Stored time.Time
+}
+
+// CacheStats counts the outcomes of cacheable requests.
+type CacheStats struct {
+	Hits        uint64 // Served from the cache without a request
+	Revalidated uint64 // Served from the cache after a 304 Not Modified
+	Misses      uint64 // Fetched in full
+}
+
+type cacheCounters struct {
+	hits, revalidated, misses uint64
+}
+
+// CacheStats returns the hit and miss counts of the Client's Cache since the
+// Client was created.
+func (c *Client) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:        atomic.LoadUint64(&c.cacheStats.hits),
+		Revalidated: atomic.LoadUint64(&c.cacheStats.revalidated),
+		Misses:      atomic.LoadUint64(&c.cacheStats.misses),
+	}
+}
+
+// DefaultCacheTTL caches user, tag and location lookups, such as /users/3,
+// for 15 minutes, and nothing else. /users/self is never cached since
+// entries are shared between access tokens.
+func DefaultCacheTTL(endpoint string) time.Duration {
+	parts := strings.Split(strings.Trim(endpoint, "/"), "/")
+	if len(parts) != 2 {
+		return 0
+	}
+	switch parts[0] {
+	case "users":
+		if parts[1] == "self" || parts[1] == "search" {
+			return 0
+		}
+	case "tags", "locations":
+		if parts[1] == "search" {
+			return 0
+		}
+	default:
+		return 0
+	}
+	return 15 * time.Minute
+}
+
+// credentialParams are left out of cache keys.
+var credentialParams = []string{"access_token", "client_id", "client_secret", "sig"}
+
+// cacheKey returns the URL of req without credentials.
+func cacheKey(req *http.Request) string {
+	u := *req.URL
+	q := u.Query()
+	for _, p := range credentialParams {
+		q.Del(p)
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// cacheTTL returns how long the response to req may be cached, or zero if it
+// may not.
+func (c *Client) cacheTTL(req *http.Request) time.Duration {
+	if c.Cache == nil || req.Method != "GET" {
+		return 0
+	}
+	ttl := c.CacheTTL
+	if ttl == nil {
+		ttl = DefaultCacheTTL
+	}
+	return ttl(c.endpoint(req.URL))
+}
+
+// sendCached sends req through the Client's Cache, which must cache it for
+// ttl. It returns a fresh entry as is, revalidates a stale one and stores a
+// new one.
+func (c *Client) sendCached(req *http.Request, ttl time.Duration) (*http.Response, error) {
+	key := cacheKey(req)
+	e, ok := c.Cache.Get(key)
+	if ok && time.Since(e.Stored) < ttl {
+		atomic.AddUint64(&c.cacheStats.hits, 1)
+		return e.response(req), nil
+	}
+
+	if ok {
+		if etag := e.Header.Get("Etag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lm := e.Header.Get("Last-Modified"); lm != "" {
+			req.Header.Set("If-Modified-Since", lm)
+		}
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	if ok && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		atomic.AddUint64(&c.cacheStats.revalidated, 1)
+		e = &CacheEntry{Header: e.Header, Body: e.Body, Stored: time.Now()}
+		c.Cache.Set(key, e)
+		return e.response(req), nil
+	}
+
+	atomic.AddUint64(&c.cacheStats.misses, 1)
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	c.Cache.Set(key, &CacheEntry{Header: resp.Header, Body: body, Stored: time.Now()})
+	return resp, nil
+}
+
+// response returns e as the response to req.
+func (e *CacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header,
+		Body:          ioutil.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// LRUCache is an in-memory Cache holding up to a fixed number of entries,
+// evicting the least recently used one first. The zero value isn't usable;
+// create one with NewLRUCache.
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // of *lruItem, most recently used first
+	entries map[string]*list.Element
+}
+
+type lruItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewLRUCache returns an LRUCache holding up to size entries. A size of zero
+// or less means no limit: entries are never evicted.
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Get returns the entry stored under key.
+func (c *LRUCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*lruItem).entry, true
+}
+
+// Set stores e under key, evicting the least recently used entry if the
+// cache is full.
+func (c *LRUCache) Set(key string, e *CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*lruItem).entry = e
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruItem{key, e})
+	for c.size > 0 && c.order.Len() > c.size {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.entries, el.Value.(*lruItem).key)
+	}
+}
+
+// Delete removes the entry stored under key.
+func (c *LRUCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.order.Remove(el)
+		delete(c.entries, key)
+	}
+}
+
+// Len returns the number of entries in the cache.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// DiskCache is a Cache storing each entry as a JSON file in a directory.
+// Entries are never evicted; remove the directory's files to reclaim space.
+type DiskCache struct {
+	Dir string
+}
+
+// NewDiskCache returns a DiskCache storing entries in dir, which is created
+// on the first Set if it doesn't exist.
+func NewDiskCache(dir string) *DiskCache {
+	return &DiskCache{Dir: dir}
+}
+
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get returns the entry stored under key.
+func (c *DiskCache) Get(key string) (*CacheEntry, bool) {
+	data, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	e := new(CacheEntry)
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, false
+	}
+	return e, true
+}
+
+// Set stores e under key. The file is written atomically, so concurrent
+// readers never see a partial entry.
+func (c *DiskCache) Set(key string, e *CacheEntry) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return
+	}
+	f, err := ioutil.TempFile(c.Dir, "tmp-")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return
+	}
+	if err := os.Rename(f.Name(), c.path(key)); err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+// Delete removes the entry stored under key.
+func (c *DiskCache) Delete(key string) {
+	os.Remove(c.path(key))
+}
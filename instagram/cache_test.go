@@ -0,0 +1,197 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// serveUser serves user 1 with the given ETag, answering conditional
+// requests carrying it with 304. It returns a pointer to the number of
+// requests received.
+func serveUser(etag string) *int {
+	calls := 0
+	mux.HandleFunc("/users/1", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprint(w, `{"data":{"id":"1","username":"u"}}`)
+	})
+	return &calls
+}
+
+func TestDo_cacheHit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := serveUser(`"v1"`)
+	client.Cache = NewLRUCache(10)
+
+	for _, token := range []string{"a", "b"} {
+		client.AccessToken = token
+		user, err := client.Users.Get("1")
+		if err != nil {
+			t.Fatalf("Users.Get returned error: %v", err)
+		}
+		if user.Username != "u" {
+			t.Errorf("Users.Get returned %+v, want username u", user)
+		}
+	}
+
+	if *calls != 1 {
+		t.Errorf("Server received %d requests, want 1", *calls)
+	}
+	if got, want := client.CacheStats(), (CacheStats{Hits: 1, Misses: 1}); got != want {
+		t.Errorf("CacheStats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDo_cacheRevalidate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := serveUser(`"v1"`)
+	client.Cache = NewLRUCache(10)
+	client.CacheTTL = func(string) time.Duration { return time.Nanosecond }
+
+	for i := 0; i < 2; i++ {
+		user, err := client.Users.Get("1")
+		if err != nil {
+			t.Fatalf("Users.Get returned error: %v", err)
+		}
+		if user.Username != "u" {
+			t.Errorf("Users.Get returned %+v, want username u", user)
+		}
+	}
+
+	if *calls != 2 {
+		t.Errorf("Server received %d requests, want 2", *calls)
+	}
+	if got, want := client.CacheStats(), (CacheStats{Revalidated: 1, Misses: 1}); got != want {
+		t.Errorf("CacheStats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDo_cacheSkipped(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/users/self/feed", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"data":[]}`)
+	})
+	client.Cache = NewLRUCache(10)
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.Users.MediaFeed(nil); err != nil {
+			t.Fatalf("Users.MediaFeed returned error: %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Server received %d requests, want 2", calls)
+	}
+	if got := client.CacheStats(); got != (CacheStats{}) {
+		t.Errorf("CacheStats() = %+v, want none", got)
+	}
+}
+
+func TestDefaultCacheTTL(t *testing.T) {
+	tests := map[string]bool{
+		"/users/3":              true,
+		"/tags/nofilter":        true,
+		"/locations/1":          true,
+		"/users/self":           false,
+		"/users/search":         false,
+		"/users/3/media/recent": false,
+		"/tags/search":          false,
+		"/media/1":              false,
+	}
+	for endpoint, cached := range tests {
+		if got := DefaultCacheTTL(endpoint) > 0; got != cached {
+			t.Errorf("DefaultCacheTTL(%q) > 0 = %v, want %v", endpoint, got, cached)
+		}
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://api.instagram.com/v1/users/1?access_token=t&client_id=c&sig=s&count=2", nil)
+	if got, want := cacheKey(req), "https://api.instagram.com/v1/users/1?count=2"; got != want {
+		t.Errorf("cacheKey = %q, want %q", got, want)
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	c := NewLRUCache(2)
+	c.Set("a", &CacheEntry{Body: []byte("a")})
+	c.Set("b", &CacheEntry{Body: []byte("b")})
+	c.Get("a")
+	c.Set("c", &CacheEntry{Body: []byte("c")})
+
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("LRUCache kept the least recently used entry")
+	}
+	for _, key := range []string{"a", "c"} {
+		if e, ok := c.Get(key); !ok || string(e.Body) != key {
+			t.Errorf("LRUCache.Get(%q) = %v, %v, want entry %q", key, e, ok, key)
+		}
+	}
+
+	c.Delete("a")
+	if c.Len() != 1 {
+		t.Errorf("LRUCache.Len() = %d, want 1", c.Len())
+	}
+}
+
+func TestLRUCache_unbounded(t *testing.T) {
+	c := NewLRUCache(0)
+	for i := 0; i < 100; i++ {
+		c.Set(strconv.Itoa(i), &CacheEntry{})
+	}
+	if c.Len() != 100 {
+		t.Errorf("LRUCache.Len() = %d, want 100", c.Len())
+	}
+	if _, ok := c.Get("0"); !ok {
+		t.Errorf("LRUCache of size 0 evicted an entry")
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "instagram-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := NewDiskCache(dir + "/sub")
+	if _, ok := c.Get("k"); ok {
+		t.Errorf("DiskCache.Get returned an entry before Set")
+	}
+
+	stored := time.Unix(1372176000, 0).UTC()
+	c.Set("k", &CacheEntry{Header: http.Header{"Etag": {`"v1"`}}, Body: []byte("body"), Stored: stored})
+	e, ok := c.Get("k")
+	if !ok {
+		t.Fatalf("DiskCache.Get returned no entry after Set")
+	}
+	if string(e.Body) != "body" || e.Header.Get("ETag") != `"v1"` || !e.Stored.Equal(stored) {
+		t.Errorf("DiskCache.Get returned %+v", e)
+	}
+
+	c.Delete("k")
+	if _, ok := c.Get("k"); ok {
+		t.Errorf("DiskCache.Get returned an entry after Delete")
+	}
+}
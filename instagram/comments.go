@@ -6,6 +6,7 @@
 package instagram
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 )
@@ -20,18 +21,23 @@ type CommentsService struct {
 
 // Comment represents a comment on Instagram's media.
 type Comment struct {
-	CreatedTime int64  `json:"created_time,string,omitempty"`
-	Text        string `json:"text,omitempty"`
-	From        *User  `json:"from,omitempty"`
-	ID          string `json:"id,omitempty"`
+	CreatedTime Timestamp `json:"created_time,omitzero"`
+	Text        string    `json:"text,omitempty"`
+	From        *User     `json:"from,omitempty"`
+	ID          string    `json:"id,omitempty"`
 }
 
 // MediaComments gets a full list of comments on a media.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/comments/#get_media_comments
 func (s *CommentsService) MediaComments(mediaId string) ([]Comment, error) {
+	return s.MediaCommentsContext(context.Background(), mediaId)
+}
+
+// MediaCommentsContext is like MediaComments but takes a context that controls the request.
+func (s *CommentsService) MediaCommentsContext(ctx context.Context, mediaId string) ([]Comment, error) {
 	u := fmt.Sprintf("media/%v/comments", mediaId)
-	req, err := s.client.NewRequest("GET", u, "")
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
 	if err != nil {
 		return nil, err
 	}
@@ -45,12 +51,17 @@ func (s *CommentsService) MediaComments(mediaId string) ([]Comment, error) {
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/comments/#post_media_comments
 func (s *CommentsService) Add(mediaId string, text []string) error {
+	return s.AddContext(context.Background(), mediaId, text)
+}
+
+// AddContext is like Add but takes a context that controls the request.
+func (s *CommentsService) AddContext(ctx context.Context, mediaId string, text []string) error {
 	u := fmt.Sprintf("media/%v/comments", mediaId)
 	params := url.Values{
 		"text": text,
 	}
 
-	req, err := s.client.NewRequest("POST", u, params.Encode())
+	req, err := s.client.NewRequestContext(ctx, "POST", u, params.Encode())
 	if err != nil {
 		return err
 	}
@@ -64,8 +75,13 @@ func (s *CommentsService) Add(mediaId string, text []string) error {
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/comments/#delete_media_comments
 func (s *CommentsService) Delete(mediaId, commentId string) error {
+	return s.DeleteContext(context.Background(), mediaId, commentId)
+}
+
+// DeleteContext is like Delete but takes a context that controls the request.
+func (s *CommentsService) DeleteContext(ctx context.Context, mediaId, commentId string) error {
 	u := fmt.Sprintf("media/%v/comments/%v", mediaId, commentId)
-	req, err := s.client.NewRequest("DELETE", u, "")
+	req, err := s.client.NewRequestContext(ctx, "DELETE", u, "")
 	if err != nil {
 		return err
 	}
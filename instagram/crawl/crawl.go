@@ -0,0 +1,295 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package crawl walks the follow graph of Instagram users.
+
+	c := &crawl.Crawler{
+		Client:    client,
+		Direction: crawl.Follows,
+		MaxDepth:  2,
+	}
+	g, err := c.Crawl(ctx, "3")
+	g.WriteDOT(os.Stdout)
+
+Private accounts, whose relationships the API refuses to list, are recorded
+in Graph.Private and not expanded. When the API reports that the rate limit
+is exhausted, the crawl pauses and retries the user afterwards; setting the
+Client's Limiter or RatelimitPolicy avoids hitting the limit in the first
+place.
+*/
+package crawl
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/gedex/go-instagram/instagram"
+)
+
+// Direction selects the relationships followed from each user.
+type Direction int
+
+const (
+	// Follows follows the users a user follows.
+	Follows Direction = iota
+
+	// FollowedBy follows the followers of a user.
+	FollowedBy
+
+	// Both follows both.
+	Both
+)
+
+// Order is the order users are visited in.
+type Order int
+
+const (
+	// BFS visits all users at a depth before going deeper.
+	BFS Order = iota
+
+	// DFS visits the users found last first.
+	DFS
+)
+
+// Edge is a follow relationship: From follows To.
+type Edge struct {
+	From, To string
+}
+
+// Graph is the result of a crawl.
+type Graph struct {
+	// Users holds every user seen, by ID. Seeds that were never listed by
+	// the API only have their ID set.
+	Users map[string]*instagram.User
+
+	// Edges between users, in no particular order.
+	Edges []Edge
+
+	// Private holds the IDs of the users whose relationships couldn't be
+	// listed.
+	Private []string
+
+	edges map[Edge]bool
+}
+
+func (g *Graph) addUser(u *instagram.User) {
+	if old, ok := g.Users[u.ID]; !ok || old.Username == "" {
+		g.Users[u.ID] = u
+	}
+}
+
+func (g *Graph) addEdge(e Edge) {
+	if !g.edges[e] {
+		g.edges[e] = true
+		g.Edges = append(g.Edges, e)
+	}
+}
+
+// sortedEdges returns the Edges sorted by From then To.
+func (g *Graph) sortedEdges() []Edge {
+	edges := append([]Edge(nil), g.Edges...)
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return edges
+}
+
+// sortedUsers returns the Users sorted by ID.
+func (g *Graph) sortedUsers() []*instagram.User {
+	users := make([]*instagram.User, 0, len(g.Users))
+	for _, u := range g.Users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users
+}
+
+// Crawler crawls the follow graph from seed users.
+type Crawler struct {
+	Client    *instagram.Client
+	Direction Direction
+	Order     Order
+
+	// MaxDepth is the distance from the seeds up to which users are
+	// expanded. Zero only lists the relationships of the seeds.
+	MaxDepth int
+
+	// MaxUsers stops the crawl once that many users were expanded. Zero
+	// means no limit.
+	MaxUsers int
+
+	// MaxPerUser limits the relationships listed per user and direction.
+	// Zero means no limit.
+	MaxPerUser int
+
+	// Concurrency is the number of users expanded at once. It defaults to
+	// 4.
+	Concurrency int
+
+	// RatelimitPause is how long the crawl pauses after the API reported
+	// that the rate limit is exhausted, unless the error tells when the
+	// limit resets. It defaults to one minute.
+	RatelimitPause time.Duration
+}
+
+type job struct {
+	id    string
+	depth int
+}
+
+type result struct {
+	job
+	users []instagram.User
+	edges []Edge
+	err   error
+}
+
+// Crawl crawls the graph from seeds. On error, the graph crawled so far is
+// returned along with it.
+func (c *Crawler) Crawl(ctx context.Context, seeds ...string) (*Graph, error) {
+	g := &Graph{Users: make(map[string]*instagram.User), edges: make(map[Edge]bool)}
+	concurrency := c.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var queue []job
+	queued := make(map[string]bool)
+	for _, id := range seeds {
+		if !queued[id] {
+			queued[id] = true
+			queue = append(queue, job{id, 0})
+			g.addUser(&instagram.User{ID: id})
+		}
+	}
+
+	// Buffered so that expanding goroutines never block once Crawl returned.
+	results := make(chan result, concurrency)
+	inflight, expanded := 0, 0
+	var resume time.Time
+	for len(queue) > 0 || inflight > 0 {
+		for len(queue) > 0 && inflight < concurrency && (c.MaxUsers == 0 || expanded < c.MaxUsers) && !time.Now().Before(resume) {
+			var j job
+			if c.Order == DFS {
+				j, queue = queue[len(queue)-1], queue[:len(queue)-1]
+			} else {
+				j, queue = queue[0], queue[1:]
+			}
+			inflight++
+			expanded++
+			go func() { results <- c.expand(ctx, j) }()
+		}
+		if inflight == 0 {
+			if len(queue) == 0 || (c.MaxUsers > 0 && expanded >= c.MaxUsers) {
+				break
+			}
+			// Paused by the rate limit.
+			if err := sleep(ctx, time.Until(resume)); err != nil {
+				return g, err
+			}
+			continue
+		}
+
+		var r result
+		select {
+		case r = <-results:
+		case <-ctx.Done():
+			return g, ctx.Err()
+		}
+		inflight--
+
+		switch {
+		case r.err == nil:
+		case errors.Is(r.err, instagram.ErrAPINotAllowed):
+			g.Private = append(g.Private, r.id)
+			continue
+		case errors.Is(r.err, instagram.ErrOAuthRateLimit):
+			resume = c.resumeTime(r.err)
+			queue = append(queue, r.job)
+			expanded--
+			continue
+		default:
+			return g, r.err
+		}
+
+		for i := range r.users {
+			u := &r.users[i]
+			g.addUser(u)
+			if r.depth < c.MaxDepth && !queued[u.ID] {
+				queued[u.ID] = true
+				queue = append(queue, job{u.ID, r.depth + 1})
+			}
+		}
+		for _, e := range r.edges {
+			g.addEdge(e)
+		}
+	}
+	return g, nil
+}
+
+// expand lists the relationships of a user.
+func (c *Crawler) expand(ctx context.Context, j job) result {
+	r := result{job: j}
+	rel := c.Client.Relationships
+	if c.Direction == Follows || c.Direction == Both {
+		it := rel.FollowsIterator(ctx, j.id, nil)
+		it.MaxItems = c.MaxPerUser
+		for it.Next() {
+			u := it.Value()
+			r.users = append(r.users, u)
+			r.edges = append(r.edges, Edge{j.id, u.ID})
+		}
+		if r.err = it.Err(); r.err != nil {
+			return r
+		}
+	}
+	if c.Direction == FollowedBy || c.Direction == Both {
+		it := rel.FollowedByIterator(ctx, j.id, nil)
+		it.MaxItems = c.MaxPerUser
+		for it.Next() {
+			u := it.Value()
+			r.users = append(r.users, u)
+			r.edges = append(r.edges, Edge{u.ID, j.id})
+		}
+		r.err = it.Err()
+	}
+	return r
+}
+
+// resumeTime returns when to resume crawling after the rate limit error err.
+func (c *Crawler) resumeTime(err error) time.Time {
+	var rlErr *instagram.RatelimitError
+	if errors.As(err, &rlErr) && !rlErr.Reset.IsZero() {
+		return rlErr.Reset
+	}
+	pause := c.RatelimitPause
+	if pause <= 0 {
+		pause = time.Minute
+	}
+	return time.Now().Add(pause)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
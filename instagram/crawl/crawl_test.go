@@ -0,0 +1,242 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crawl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gedex/go-instagram/instagram"
+)
+
+// follows is the follow graph served by newClient. User 4 is private.
+var follows = map[string][]string{
+	"1": {"2", "3"},
+	"2": {"3", "4"},
+	"3": {"1", "5"},
+	"4": {"1"},
+	"5": {},
+}
+
+// newClient returns a Client talking to a server listing follows. The
+// returned map counts the requests per path.
+func newClient(t *testing.T) (*instagram.Client, map[string]int) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if len(parts) != 3 || parts[0] != "users" {
+			http.NotFound(w, r)
+			return
+		}
+		id := parts[1]
+		if id == "4" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"meta":{"code":400,"error_type":"APINotAllowedError","error_message":"you cannot view this resource"}}`)
+			return
+		}
+		var ids []string
+		switch parts[2] {
+		case "follows":
+			ids = follows[id]
+		case "followed-by":
+			for from, to := range follows {
+				for _, t := range to {
+					if t == id {
+						ids = append(ids, from)
+					}
+				}
+			}
+		}
+		var data []string
+		for _, id := range ids {
+			data = append(data, fmt.Sprintf(`{"id":%q,"username":"user%v"}`, id, id))
+		}
+		fmt.Fprintf(w, `{"data":[%v]}`, strings.Join(data, ","))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(server.Close)
+	client := instagram.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	return client, requests
+}
+
+func TestCrawl(t *testing.T) {
+	client, _ := newClient(t)
+	c := &Crawler{Client: client, MaxDepth: 5}
+	g, err := c.Crawl(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("Crawl returned error: %v", err)
+	}
+
+	want := []Edge{{"1", "2"}, {"1", "3"}, {"2", "3"}, {"2", "4"}, {"3", "1"}, {"3", "5"}}
+	if got := g.sortedEdges(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Crawl returned edges %v, want %v", got, want)
+	}
+	if want := []string{"4"}; !reflect.DeepEqual(g.Private, want) {
+		t.Errorf("Crawl returned Private %v, want %v", g.Private, want)
+	}
+	if len(g.Users) != 5 || g.Users["1"].Username != "user1" {
+		t.Errorf("Crawl returned Users %v", g.Users)
+	}
+}
+
+func TestCrawl_MaxDepth(t *testing.T) {
+	client, requests := newClient(t)
+	c := &Crawler{Client: client, MaxDepth: 1}
+	g, err := c.Crawl(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("Crawl returned error: %v", err)
+	}
+
+	// Users 2 and 3 are expanded, but not the users they follow.
+	if requests["/users/5/follows"] != 0 || requests["/users/4/follows"] != 0 {
+		t.Errorf("Crawl expanded users beyond MaxDepth: %v", requests)
+	}
+	if got := len(g.Edges); got != 6 {
+		t.Errorf("Crawl returned %d edges, want 6", got)
+	}
+}
+
+func TestCrawl_Both(t *testing.T) {
+	client, requests := newClient(t)
+	c := &Crawler{Client: client, Direction: Both, Order: DFS, Concurrency: 1}
+	g, err := c.Crawl(context.Background(), "3")
+	if err != nil {
+		t.Fatalf("Crawl returned error: %v", err)
+	}
+
+	want := []Edge{{"1", "3"}, {"2", "3"}, {"3", "1"}, {"3", "5"}}
+	if got := g.sortedEdges(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Crawl returned edges %v, want %v", got, want)
+	}
+	if requests["/users/3/followed-by"] != 1 {
+		t.Errorf("Crawl didn't list followers: %v", requests)
+	}
+}
+
+func TestCrawl_MaxUsers(t *testing.T) {
+	client, requests := newClient(t)
+	c := &Crawler{Client: client, MaxDepth: 5, MaxUsers: 2, Concurrency: 1}
+	if _, err := c.Crawl(context.Background(), "1"); err != nil {
+		t.Fatalf("Crawl returned error: %v", err)
+	}
+	if got := len(requests); got != 2 {
+		t.Errorf("Crawl expanded %d users, want 2: %v", got, requests)
+	}
+}
+
+func TestCrawl_ratelimit(t *testing.T) {
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"meta":{"code":429,"error_type":"OAuthRateLimitException","error_message":"limit"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":[{"id":"2"}]}`)
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+	client := instagram.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	c := &Crawler{Client: client, RatelimitPause: time.Millisecond}
+	g, err := c.Crawl(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("Crawl returned error: %v", err)
+	}
+	if want := []Edge{{"1", "2"}}; calls != 2 || !reflect.DeepEqual(g.Edges, want) {
+		t.Errorf("Crawl made %d calls and returned edges %v, want 2 and %v", calls, g.Edges, want)
+	}
+}
+
+func testGraph() *Graph {
+	return &Graph{
+		Users: map[string]*instagram.User{
+			"1": {ID: "1", Username: "a&b"},
+			"2": {ID: "2", Username: `say "hi"`, FullName: "Two"},
+			"3": {ID: "3"},
+		},
+		Edges:   []Edge{{"2", "1"}, {"1", "2"}, {"1", "3"}},
+		Private: []string{"3"},
+	}
+}
+
+func TestGraph_WriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testGraph().WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV returned error: %v", err)
+	}
+	if want := "from,to\n1,2\n1,3\n2,1\n"; buf.String() != want {
+		t.Errorf("WriteCSV wrote %q, want %q", buf.String(), want)
+	}
+}
+
+func TestGraph_WriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testGraph().WriteDOT(&buf); err != nil {
+		t.Fatalf("WriteDOT returned error: %v", err)
+	}
+	want := `digraph instagram {
+	"1" [label="a&b"];
+	"2" [label="say \"hi\""];
+	"3" [label="3"];
+	"1" -> "2";
+	"1" -> "3";
+	"2" -> "1";
+}
+`
+	if buf.String() != want {
+		t.Errorf("WriteDOT wrote %q, want %q", buf.String(), want)
+	}
+}
+
+func TestGraph_WriteGraphML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testGraph().WriteGraphML(&buf); err != nil {
+		t.Fatalf("WriteGraphML returned error: %v", err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="username" for="node" attr.name="username" attr.type="string"></key>
+  <key id="full_name" for="node" attr.name="full_name" attr.type="string"></key>
+  <key id="private" for="node" attr.name="private" attr.type="boolean"></key>
+  <graph edgedefault="directed">
+    <node id="1">
+      <data key="username">a&amp;b</data>
+    </node>
+    <node id="2">
+      <data key="username">say &#34;hi&#34;</data>
+      <data key="full_name">Two</data>
+    </node>
+    <node id="3">
+      <data key="private">true</data>
+    </node>
+    <edge source="1" target="2"></edge>
+    <edge source="1" target="3"></edge>
+    <edge source="2" target="1"></edge>
+  </graph>
+</graphml>
+`
+	if buf.String() != want {
+		t.Errorf("WriteGraphML wrote\n%v\nwant\n%v", buf.String(), want)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crawl
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteCSV writes the edges of g as CSV, with a "from,to" header line.
+func (g *Graph) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"from", "to"})
+	for _, e := range g.sortedEdges() {
+		cw.Write([]string{e.From, e.To})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteDOT writes g as a Graphviz digraph, with users labeled by username.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph instagram {")
+	for _, u := range g.sortedUsers() {
+		label := u.Username
+		if label == "" {
+			label = u.ID
+		}
+		fmt.Fprintf(bw, "\t%s [label=%s];\n", strconv.Quote(u.ID), strconv.Quote(label))
+	}
+	for _, e := range g.sortedEdges() {
+		fmt.Fprintf(bw, "\t%s -> %s;\n", strconv.Quote(e.From), strconv.Quote(e.To))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   struct {
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	Name     string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes g as a GraphML document. Nodes carry the username and
+// full_name attributes, and a private attribute for the users whose
+// relationships couldn't be listed.
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{"username", "node", "username", "string"},
+			{"full_name", "node", "full_name", "string"},
+			{"private", "node", "private", "boolean"},
+		},
+	}
+	doc.Graph.EdgeDefault = "directed"
+
+	private := make(map[string]bool)
+	for _, id := range g.Private {
+		private[id] = true
+	}
+	for _, u := range g.sortedUsers() {
+		n := graphMLNode{ID: u.ID}
+		if u.Username != "" {
+			n.Data = append(n.Data, graphMLData{"username", u.Username})
+		}
+		if u.FullName != "" {
+			n.Data = append(n.Data, graphMLData{"full_name", u.FullName})
+		}
+		if private[u.ID] {
+			n.Data = append(n.Data, graphMLData{"private", "true"})
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, n)
+	}
+	for _, e := range g.sortedEdges() {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{e.From, e.To})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,366 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package download fetches the image and video files of Instagram media.
+
+	d := &download.Downloader{Dir: "archive"}
+	paths, err := d.Download(ctx, &media)
+
+Files are named after the media's creation time and ID, so downloading the
+same media twice is a no-op. Every item of a carousel is downloaded to its
+own file, whose name ends with the item's index. Interrupted downloads are
+resumed from where they stopped.
+*/
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gedex/go-instagram/instagram"
+)
+
+// ErrNoAsset is returned, wrapped, when the Policy finds no asset to
+// download in a media.
+var ErrNoAsset = errors.New("no downloadable asset")
+
+// partSuffix is appended to the name of files being downloaded.
+const partSuffix = ".part"
+
+// Asset is a single downloadable file of a media item.
+type Asset struct {
+	URL    string
+	Width  int
+	Height int
+	Video  bool
+}
+
+// Policy picks the asset to download of an item of a media, as returned by
+// Media.Items. It returns false if the item has none that fits.
+type Policy func(item *instagram.MediaItem) (Asset, bool)
+
+// Largest picks the video with the highest resolution for videos, and the
+// image with the highest resolution otherwise.
+func Largest(item *instagram.MediaItem) (Asset, bool) {
+	return pick(assets(item, true), func(a, b Asset) bool { return a.Width*a.Height > b.Width*b.Height })
+}
+
+// Smallest picks the video with the lowest resolution for videos, and the
+// image with the lowest resolution otherwise.
+func Smallest(item *instagram.MediaItem) (Asset, bool) {
+	return pick(assets(item, true), func(a, b Asset) bool { return a.Width*a.Height < b.Width*b.Height })
+}
+
+// LargestImage picks the image with the highest resolution, which for
+// videos is the cover image.
+func LargestImage(item *instagram.MediaItem) (Asset, bool) {
+	return pick(assets(item, false), func(a, b Asset) bool { return a.Width*a.Height > b.Width*b.Height })
+}
+
+// assets returns the videos of item if it has any and videos is set, and
+// its images otherwise.
+func assets(item *instagram.MediaItem, videos bool) []Asset {
+	var list []Asset
+	if videos && item.Videos != nil {
+		for _, v := range []*instagram.MediaVideo{item.Videos.LowBandwidth, item.Videos.LowResolution, item.Videos.StandardResolution} {
+			if v != nil && v.URL != "" {
+				list = append(list, Asset{URL: v.URL, Width: v.Width, Height: v.Height, Video: true})
+			}
+		}
+	}
+	if len(list) == 0 && item.Images != nil {
+		for _, i := range []*instagram.MediaImage{item.Images.Thumbnail, item.Images.LowResolution, item.Images.StandardResolution} {
+			if i != nil && i.URL != "" {
+				list = append(list, Asset{URL: i.URL, Width: i.Width, Height: i.Height})
+			}
+		}
+	}
+	return list
+}
+
+func pick(list []Asset, better func(a, b Asset) bool) (Asset, bool) {
+	if len(list) == 0 {
+		return Asset{}, false
+	}
+	best := list[0]
+	for _, a := range list[1:] {
+		if better(a, best) {
+			best = a
+		}
+	}
+	return best, true
+}
+
+// Result is the outcome of downloading a single media.
+type Result struct {
+	Media *instagram.Media
+
+	// Paths of the downloaded files, as returned by Downloader.Download.
+	Paths []string
+
+	// Skipped is set when all the files were already downloaded.
+	Skipped bool
+
+	Err error
+}
+
+// Downloader downloads media files into a directory.
+type Downloader struct {
+	// Dir is the directory files are written to. It's created if needed.
+	Dir string
+
+	// Policy picks the asset to download. Largest is used when it's nil.
+	Policy Policy
+
+	// Concurrency bounds the number of simultaneous downloads made by
+	// DownloadAll. It defaults to 4.
+	Concurrency int
+
+	// HTTPClient is used for downloads. http.DefaultClient is used when
+	// it's nil.
+	HTTPClient *http.Client
+}
+
+// FileName returns the name, without extension, under which the file of m
+// is stored: its creation time in unix seconds followed by its ID. The
+// files of a carousel's items are named ItemFileName instead.
+func FileName(m *instagram.Media) string {
+	var created int64
+	if !m.CreatedTime.IsZero() {
+		created = m.CreatedTime.Unix()
+	}
+	return fmt.Sprintf("%d_%s", created, m.ID)
+}
+
+// ItemFileName returns the name, without extension, under which the file of
+// item i, starting at 0, of m is stored: the FileName of m for media that
+// aren't carousels, and the FileName followed by the index otherwise.
+func ItemFileName(m *instagram.Media, i int) string {
+	if len(m.CarouselMedia) == 0 {
+		return FileName(m)
+	}
+	return fmt.Sprintf("%s_%d", FileName(m), i)
+}
+
+// Download fetches the asset of every item of m picked by the Downloader's
+// Policy and returns the paths they were written to, one per item as
+// returned by Media.Items. The path of an item without an asset is empty;
+// if no item has one, an error wrapping ErrNoAsset is returned.
+func (d *Downloader) Download(ctx context.Context, m *instagram.Media) ([]string, error) {
+	paths, _, err := d.download(ctx, m)
+	return paths, err
+}
+
+// DownloadAll downloads every media received from in, running up to
+// Concurrency downloads at once. The returned channel yields a Result per
+// media, in completion order, and is closed once in is closed and all
+// downloads are done.
+func (d *Downloader) DownloadAll(ctx context.Context, in <-chan *instagram.Media) <-chan Result {
+	n := d.Concurrency
+	if n <= 0 {
+		n = 4
+	}
+	out := make(chan Result)
+
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			for m := range in {
+				paths, skipped, err := d.download(ctx, m)
+				select {
+				case out <- Result{Media: m, Paths: paths, Skipped: skipped, Err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+func (d *Downloader) download(ctx context.Context, m *instagram.Media) ([]string, bool, error) {
+	policy := d.Policy
+	if policy == nil {
+		policy = Largest
+	}
+	items := m.Items()
+	paths := make([]string, len(items))
+	found, skipped := false, true
+	for i, item := range items {
+		asset, ok := policy(item)
+		if !ok {
+			continue
+		}
+		name := filepath.Join(d.Dir, ItemFileName(m, i)+extension(asset))
+		fetched, err := d.downloadAsset(ctx, asset, name)
+		if err != nil {
+			return nil, false, err
+		}
+		paths[i] = name
+		found = true
+		skipped = skipped && !fetched
+	}
+	if !found {
+		return nil, false, fmt.Errorf("download: media %v has %w", m.ID, ErrNoAsset)
+	}
+	return paths, skipped, nil
+}
+
+// downloadAsset downloads asset to the file at name, unless it exists, and
+// reports whether it was fetched.
+func (d *Downloader) downloadAsset(ctx context.Context, asset Asset, name string) (bool, error) {
+	if err := os.MkdirAll(d.Dir, 0755); err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(name); err == nil {
+		return false, nil
+	}
+
+	part := name + partSuffix
+	if err := d.fetch(ctx, asset, part); err != nil {
+		return false, err
+	}
+	return true, os.Rename(part, name)
+}
+
+// fetch downloads asset into the file at name, resuming from its current
+// size if it exists.
+func (d *Downloader) fetch(ctx context.Context, asset Asset, name string) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", asset.URL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", instagram.UserAgent)
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	hc := d.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		// Resuming.
+	case resp.StatusCode == http.StatusOK:
+		// Either a fresh download or the server ignored the Range header.
+		offset = 0
+		if err := f.Truncate(0); err != nil {
+			return err
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// The part file is already complete.
+		return nil
+	default:
+		return fmt.Errorf("download: GET %v: %v", asset.URL, resp.Status)
+	}
+
+	if err := checkContentType(resp, asset); err != nil {
+		return err
+	}
+
+	n, err := io.Copy(f, resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.ContentLength >= 0 && n != resp.ContentLength {
+		return fmt.Errorf("download: GET %v: got %d bytes, want %d", asset.URL, n, resp.ContentLength)
+	}
+	if total := totalLength(resp); total >= 0 && offset+n != total {
+		return fmt.Errorf("download: GET %v: file has %d bytes, want %d", asset.URL, offset+n, total)
+	}
+	return nil
+}
+
+// checkContentType verifies that resp carries the kind of content asset is.
+func checkContentType(resp *http.Response, asset Asset) error {
+	ct := resp.Header.Get("Content-Type")
+	if ct == "" {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return fmt.Errorf("download: GET %v: %v", asset.URL, err)
+	}
+	want := "image/"
+	if asset.Video {
+		want = "video/"
+	}
+	if !strings.HasPrefix(mediaType, want) && mediaType != "application/octet-stream" {
+		return fmt.Errorf("download: GET %v: unexpected content type %v", asset.URL, mediaType)
+	}
+	return nil
+}
+
+// totalLength returns the full size of the file served in resp, or -1 if
+// it isn't known.
+func totalLength(resp *http.Response) int64 {
+	if resp.StatusCode == http.StatusOK {
+		return resp.ContentLength
+	}
+	// Content-Range: bytes 100-199/200
+	cr := resp.Header.Get("Content-Range")
+	i := strings.LastIndex(cr, "/")
+	if i < 0 {
+		return -1
+	}
+	total, err := strconv.ParseInt(cr[i+1:], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return total
+}
+
+// extension returns the file extension of asset, from its URL or, failing
+// that, its kind.
+func extension(asset Asset) string {
+	if u, err := url.Parse(asset.URL); err == nil {
+		if ext := path.Ext(u.Path); ext != "" {
+			return ext
+		}
+	}
+	if asset.Video {
+		return ".mp4"
+	}
+	return ".jpg"
+}
@@ -0,0 +1,214 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package download
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gedex/go-instagram/instagram"
+)
+
+var content = bytes.Repeat([]byte("0123456789"), 100)
+
+// newServer serves content for every path, honoring Range requests. Paths
+// ending in .mp4 are served as video, .txt as text, the rest as image.
+func newServer(ranges *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ranges != nil {
+			*ranges = append(*ranges, r.Header.Get("Range"))
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, ".mp4"):
+			w.Header().Set("Content-Type", "video/mp4")
+		case strings.HasSuffix(r.URL.Path, ".txt"):
+			w.Header().Set("Content-Type", "text/plain")
+		default:
+			w.Header().Set("Content-Type", "image/jpeg")
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+	}))
+}
+
+func testMedia(base string) *instagram.Media {
+	return &instagram.Media{
+		ID:          "1_2",
+		CreatedTime: instagram.Unix(1380000000),
+		Images: &instagram.MediaImages{
+			Thumbnail:          &instagram.MediaImage{URL: base + "/t.jpg", Width: 150, Height: 150},
+			LowResolution:      &instagram.MediaImage{URL: base + "/l.jpg", Width: 306, Height: 306},
+			StandardResolution: &instagram.MediaImage{URL: base + "/s.jpg", Width: 640, Height: 640},
+		},
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	m := testMedia("http://x")
+	m.Videos = &instagram.MediaVideos{
+		LowResolution:      &instagram.MediaVideo{URL: "http://x/l.mp4", Width: 480, Height: 480},
+		StandardResolution: &instagram.MediaVideo{URL: "http://x/s.mp4", Width: 640, Height: 640},
+	}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		want   string
+	}{
+		{"Largest", Largest, "http://x/s.mp4"},
+		{"Smallest", Smallest, "http://x/l.mp4"},
+		{"LargestImage", LargestImage, "http://x/s.jpg"},
+	}
+	for _, tt := range tests {
+		a, ok := tt.policy(m.Items()[0])
+		if !ok || a.URL != tt.want {
+			t.Errorf("%v picked %v, %v, want %v", tt.name, a.URL, ok, tt.want)
+		}
+	}
+
+	if _, ok := Largest(&instagram.MediaItem{}); ok {
+		t.Errorf("Largest picked an asset of an item without any")
+	}
+}
+
+func TestDownloader_Download(t *testing.T) {
+	server := newServer(nil)
+	defer server.Close()
+
+	dir := t.TempDir()
+	d := &Downloader{Dir: dir}
+
+	paths, err := d.Download(context.Background(), testMedia(server.URL))
+	if err != nil {
+		t.Fatalf("Download returned error: %v", err)
+	}
+	if want := []string{filepath.Join(dir, "1380000000_1_2.jpg")}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("Download wrote %v, want %v", paths, want)
+	}
+	if got, _ := ioutil.ReadFile(paths[0]); !bytes.Equal(got, content) {
+		t.Errorf("Download wrote %d bytes, want the %d served", len(got), len(content))
+	}
+}
+
+func TestDownloader_carousel(t *testing.T) {
+	server := newServer(nil)
+	defer server.Close()
+
+	dir := t.TempDir()
+	d := &Downloader{Dir: dir}
+
+	m := testMedia(server.URL)
+	m.Type = instagram.MediaTypeCarousel
+	m.CarouselMedia = []*instagram.MediaItem{
+		{Type: instagram.MediaTypeImage, Images: m.Images},
+		{Type: instagram.MediaTypeImage},
+		{Type: instagram.MediaTypeVideo, Videos: &instagram.MediaVideos{
+			StandardResolution: &instagram.MediaVideo{URL: server.URL + "/s.mp4", Width: 640, Height: 640},
+		}},
+	}
+	paths, err := d.Download(context.Background(), m)
+	if err != nil {
+		t.Fatalf("Download returned error: %v", err)
+	}
+	want := []string{
+		filepath.Join(dir, "1380000000_1_2_0.jpg"),
+		"",
+		filepath.Join(dir, "1380000000_1_2_2.mp4"),
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("Download wrote %v, want %v", paths, want)
+	}
+	for _, p := range []string{want[0], want[2]} {
+		if got, _ := ioutil.ReadFile(p); !bytes.Equal(got, content) {
+			t.Errorf("Download wrote %d bytes to %v, want the %d served", len(got), p, len(content))
+		}
+	}
+}
+
+func TestDownloader_resume(t *testing.T) {
+	var ranges []string
+	server := newServer(&ranges)
+	defer server.Close()
+
+	dir := t.TempDir()
+	part := filepath.Join(dir, "1380000000_1_2.jpg"+partSuffix)
+	if err := ioutil.WriteFile(part, content[:300], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d := &Downloader{Dir: dir}
+	paths, err := d.Download(context.Background(), testMedia(server.URL))
+	if err != nil {
+		t.Fatalf("Download returned error: %v", err)
+	}
+	if len(ranges) != 1 || ranges[0] != "bytes=300-" {
+		t.Errorf("Download sent Range headers %q, want [bytes=300-]", ranges)
+	}
+	if got, _ := ioutil.ReadFile(paths[0]); !bytes.Equal(got, content) {
+		t.Errorf("Resumed download has %d bytes, want the %d served", len(got), len(content))
+	}
+	if _, err := os.Stat(part); !os.IsNotExist(err) {
+		t.Errorf("Part file still exists after download")
+	}
+
+	// A second download is skipped.
+	ranges = nil
+	if _, err := d.Download(context.Background(), testMedia(server.URL)); err != nil {
+		t.Errorf("Download returned error: %v", err)
+	}
+	if len(ranges) != 0 {
+		t.Errorf("Download of an existing file sent %d requests, want 0", len(ranges))
+	}
+}
+
+func TestDownloader_contentType(t *testing.T) {
+	server := newServer(nil)
+	defer server.Close()
+
+	m := &instagram.Media{
+		ID:     "1",
+		Images: &instagram.MediaImages{StandardResolution: &instagram.MediaImage{URL: server.URL + "/s.txt"}},
+	}
+	d := &Downloader{Dir: t.TempDir()}
+	if _, err := d.Download(context.Background(), m); err == nil {
+		t.Errorf("Download expected error for a non-image response")
+	}
+}
+
+func TestDownloader_DownloadAll(t *testing.T) {
+	server := newServer(nil)
+	defer server.Close()
+
+	d := &Downloader{Dir: t.TempDir(), Concurrency: 2}
+	in := make(chan *instagram.Media)
+	go func() {
+		for _, id := range []string{"1", "2", "3", "4", "5"} {
+			m := testMedia(server.URL)
+			m.ID = id
+			in <- m
+		}
+		close(in)
+	}()
+
+	seen := make(map[string]bool)
+	for r := range d.DownloadAll(context.Background(), in) {
+		if r.Err != nil {
+			t.Errorf("DownloadAll returned error for %v: %v", r.Media.ID, r.Err)
+		}
+		seen[r.Media.ID] = true
+	}
+	if len(seen) != 5 {
+		t.Errorf("DownloadAll returned %d results, want 5", len(seen))
+	}
+}
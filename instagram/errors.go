@@ -0,0 +1,56 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"errors"
+)
+
+// Errors reported by the API in meta.error_type. An *ErrorResponse matches
+// the value for its error type with errors.Is.
+//
+// Instagram API docs: http://instagram.com/developer/endpoints/#structure
+var (
+	// ErrOAuthAccessToken means the access token is missing, invalid or
+	// expired. A new token has to be obtained.
+	ErrOAuthAccessToken = errors.New("instagram: OAuthAccessTokenException")
+
+	// ErrOAuthRateLimit means the rate limit for the token or client was
+	// exceeded. It's also matched by *RatelimitError.
+	ErrOAuthRateLimit = errors.New("instagram: OAuthRateLimitException")
+
+	// ErrOAuthPermissions means the token lacks the scope required by the
+	// endpoint.
+	ErrOAuthPermissions = errors.New("instagram: OAuthPermissionsException")
+
+	// ErrOAuthParameters means the request's OAuth parameters are invalid.
+	ErrOAuthParameters = errors.New("instagram: OAuthParametersException")
+
+	// ErrOAuthForbidden means the client is not allowed to make the request,
+	// e.g. because it's not signed.
+	ErrOAuthForbidden = errors.New("instagram: OAuthForbiddenException")
+
+	// ErrAPINotAllowed means the resource isn't visible to the authenticated
+	// user, typically because it belongs to a private user.
+	ErrAPINotAllowed = errors.New("instagram: APINotAllowedError")
+
+	// ErrAPINotFound means the requested object doesn't exist.
+	ErrAPINotFound = errors.New("instagram: APINotFoundError")
+
+	// ErrAPIInvalidParameters means a parameter of the request is invalid.
+	ErrAPIInvalidParameters = errors.New("instagram: APIInvalidParametersError")
+)
+
+var errorTypes = map[string]error{
+	"OAuthAccessTokenException": ErrOAuthAccessToken,
+	"OAuthRateLimitException":   ErrOAuthRateLimit,
+	"OAuthPermissionsException": ErrOAuthPermissions,
+	"OAuthParametersException":  ErrOAuthParameters,
+	"OAuthForbiddenException":   ErrOAuthForbidden,
+	"APINotAllowedError":        ErrAPINotAllowed,
+	"APINotFoundError":          ErrAPINotFound,
+	"APIInvalidParametersError": ErrAPIInvalidParameters,
+}
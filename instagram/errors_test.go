@@ -0,0 +1,126 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCheckResponse_errorTypes(t *testing.T) {
+	tests := []struct {
+		status    int
+		errorType string
+		want      error
+	}{
+		{http.StatusBadRequest, "OAuthAccessTokenException", ErrOAuthAccessToken},
+		{http.StatusTooManyRequests, "OAuthRateLimitException", ErrOAuthRateLimit},
+		{http.StatusBadRequest, "OAuthPermissionsException", ErrOAuthPermissions},
+		{http.StatusBadRequest, "OAuthParametersException", ErrOAuthParameters},
+		{http.StatusForbidden, "OAuthForbiddenException", ErrOAuthForbidden},
+		{http.StatusBadRequest, "APINotAllowedError", ErrAPINotAllowed},
+		{http.StatusBadRequest, "APINotFoundError", ErrAPINotFound},
+		{http.StatusBadRequest, "APIInvalidParametersError", ErrAPIInvalidParameters},
+	}
+
+	for _, tt := range tests {
+		setup()
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			fmt.Fprintf(w, `{"meta":{"code":%d,"error_type":"%s","error_message":"m"}}`, tt.status, tt.errorType)
+		})
+
+		req, _ := client.NewRequest("GET", "/", "")
+		_, err := client.Do(req, nil)
+		teardown()
+
+		if !errors.Is(err, tt.want) {
+			t.Errorf("Do returned error %v, want it to match %v", err, tt.want)
+		}
+		if errors.Is(err, ErrAPINotFound) != (tt.want == ErrAPINotFound) {
+			t.Errorf("Do returned error %v which wrongly matches %v", err, ErrAPINotFound)
+		}
+
+		var errResp *ErrorResponse
+		if !errors.As(err, &errResp) {
+			t.Errorf("Do returned error %v, want *ErrorResponse", err)
+		} else if errResp.Meta.ErrorType != tt.errorType {
+			t.Errorf("ErrorResponse.Meta.ErrorType = %v, want %v", errResp.Meta.ErrorType, tt.errorType)
+		}
+	}
+}
+
+func TestCheckResponse_tooManyRequests(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	req, _ := client.NewRequest("GET", "/", "")
+	if _, err := client.Do(req, nil); !errors.Is(err, ErrOAuthRateLimit) {
+		t.Errorf("Do returned error %v, want it to match %v", err, ErrOAuthRateLimit)
+	}
+}
+
+func TestCheckResponse_notFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/body", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"meta":{"code":404,"error_type":"APINotFoundError","error_message":"this user does not exist"}}`)
+	})
+	mux.HandleFunc("/html", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+
+	req, _ := client.NewRequest("GET", "/body", "")
+	_, err := client.Do(req, nil)
+	if !errors.Is(err, ErrAPINotFound) {
+		t.Errorf("Do returned error %v, want it to match %v", err, ErrAPINotFound)
+	}
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) && errResp.Meta.ErrorMessage != "this user does not exist" {
+		t.Errorf("ErrorResponse.Meta.ErrorMessage = %q, want the message of the body", errResp.Meta.ErrorMessage)
+	}
+
+	req, _ = client.NewRequest("GET", "/html", "")
+	_, err = client.Do(req, nil)
+	if !errors.Is(err, ErrAPINotFound) {
+		t.Errorf("Do returned error %v, want it to match %v", err, ErrAPINotFound)
+	}
+	if errors.As(err, &errResp) && errResp.Meta.ErrorMessage != "Not Found" {
+		t.Errorf("ErrorResponse.Meta.ErrorMessage = %q, want Not Found", errResp.Meta.ErrorMessage)
+	}
+}
+
+func TestResponse_GetError(t *testing.T) {
+	r := &Response{Meta: &ResponseMeta{Code: 200}}
+	if err := r.GetError(); err != nil {
+		t.Errorf("GetError returned %v, want nil", err)
+	}
+
+	r.Meta = &ResponseMeta{Code: 400, ErrorType: "APINotAllowedError", ErrorMessage: "private"}
+	err := r.GetError()
+	if !errors.Is(err, ErrAPINotAllowed) {
+		t.Errorf("GetError returned %v, want it to match %v", err, ErrAPINotAllowed)
+	}
+	if want := "APINotAllowedError: private"; err.Error() != want {
+		t.Errorf("GetError().Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestRatelimitError_Is(t *testing.T) {
+	var err error = &RatelimitError{Reset: time.Now()}
+	if !errors.Is(err, ErrOAuthRateLimit) {
+		t.Errorf("RatelimitError doesn't match %v", ErrOAuthRateLimit)
+	}
+}
@@ -0,0 +1,382 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package export archives the media history of Instagram users.
+
+	f, _ := os.OpenFile("3.ndjson", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	e := &export.Exporter{
+		Client:     client,
+		Format:     export.NDJSON,
+		Checkpoint: "3.checkpoint",
+	}
+	n, err := e.Export(ctx, "3", f)
+
+The user's recent media are walked page by page, newest first, following
+next_max_id. After each page is written, the position is saved to the
+Checkpoint file; running the same export again resumes after the last
+checkpointed page, so the output must be opened for appending. A page that
+was written but not checkpointed when the export was interrupted is written
+again on resume.
+*/
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gedex/go-instagram/instagram"
+	"github.com/gedex/go-instagram/instagram/download"
+)
+
+// Format is the output format of an export.
+type Format int
+
+const (
+	// NDJSON writes one JSON encoded Record per line.
+	NDJSON Format = iota
+
+	// CSV writes a header line with Columns, then one line per Record.
+	CSV
+)
+
+// Columns are the CSV columns, in order. New columns are only ever appended.
+var Columns = []string{
+	"id", "created_time", "type", "username", "link", "caption", "tags",
+	"filter", "likes", "comments", "location_id", "location_name",
+	"latitude", "longitude", "image_url", "video_url", "asset_path",
+	"image_urls", "video_urls", "asset_paths",
+}
+
+// Record is the exported form of a media.
+type Record struct {
+	ID           string              `json:"id"`
+	CreatedTime  string              `json:"created_time"` // RFC 3339
+	Type         instagram.MediaType `json:"type"`
+	Username     string              `json:"username"`
+	Link         string              `json:"link"`
+	Caption      string              `json:"caption"`
+	Tags         []string            `json:"tags"`
+	Filter       string              `json:"filter"`
+	Likes        int                 `json:"likes"`
+	Comments     int                 `json:"comments"`
+	LocationID   string              `json:"location_id"`
+	LocationName string              `json:"location_name"`
+	Latitude     float64             `json:"latitude"`
+	Longitude    float64             `json:"longitude"`
+
+	// ImageURL, VideoURL and AssetPath are those of the first item, which
+	// for media other than carousels is the media itself.
+	ImageURL  string `json:"image_url"`
+	VideoURL  string `json:"video_url"`
+	AssetPath string `json:"asset_path"`
+
+	// Items are the items of the media, as returned by Media.Items. CSV
+	// exports list their non-empty URLs and paths, separated by spaces, in
+	// the image_urls, video_urls and asset_paths columns.
+	Items []Item `json:"items"`
+
+	// RecentComments are the comments embedded in the media by the API.
+	// They're left out of CSV exports.
+	RecentComments []Comment `json:"recent_comments,omitempty"`
+}
+
+// Item is the exported form of an item of a media.
+type Item struct {
+	Type     instagram.MediaType `json:"type"`
+	ImageURL string              `json:"image_url"`
+	VideoURL string              `json:"video_url"`
+
+	// AssetPath is the file the item was downloaded to, if any.
+	AssetPath string `json:"asset_path"`
+}
+
+// Comment is the exported form of a comment.
+type Comment struct {
+	ID          string `json:"id"`
+	CreatedTime string `json:"created_time"`
+	Username    string `json:"username"`
+	Text        string `json:"text"`
+}
+
+// NewRecord returns the Record of m.
+func NewRecord(m *instagram.Media) *Record {
+	r := &Record{
+		ID:          m.ID,
+		CreatedTime: formatTime(m.CreatedTime),
+		Type:        m.Type,
+		Link:        m.Link,
+		Tags:        m.Tags,
+		Filter:      m.Filter,
+	}
+	for _, item := range m.Items() {
+		r.Items = append(r.Items, Item{
+			Type:     item.Type,
+			ImageURL: imageURL(item),
+			VideoURL: videoURL(item),
+		})
+	}
+	r.ImageURL = r.Items[0].ImageURL
+	r.VideoURL = r.Items[0].VideoURL
+	if r.Tags == nil {
+		r.Tags = []string{}
+	}
+	if m.User != nil {
+		r.Username = m.User.Username
+	}
+	if m.Caption != nil {
+		r.Caption = m.Caption.Text
+	}
+	if m.Likes != nil {
+		r.Likes = m.Likes.Count
+	}
+	if m.Comments != nil {
+		r.Comments = m.Comments.Count
+		for _, c := range m.Comments.Data {
+			rc := Comment{ID: c.ID, CreatedTime: formatTime(c.CreatedTime), Text: c.Text}
+			if c.From != nil {
+				rc.Username = c.From.Username
+			}
+			r.RecentComments = append(r.RecentComments, rc)
+		}
+	}
+	if l := m.Location; l != nil {
+		r.LocationID = string(l.ID)
+		r.LocationName = l.Name
+		r.Latitude = l.Latitude
+		r.Longitude = l.Longitude
+	}
+	return r
+}
+
+// csv returns the fields of r in the order of Columns.
+func (r *Record) csv() []string {
+	return []string{
+		r.ID, r.CreatedTime, string(r.Type), r.Username, r.Link, r.Caption,
+		strings.Join(r.Tags, " "), r.Filter, strconv.Itoa(r.Likes),
+		strconv.Itoa(r.Comments), r.LocationID, r.LocationName,
+		formatFloat(r.Latitude), formatFloat(r.Longitude), r.ImageURL,
+		r.VideoURL, r.AssetPath,
+		r.joinItems(func(i Item) string { return i.ImageURL }),
+		r.joinItems(func(i Item) string { return i.VideoURL }),
+		r.joinItems(func(i Item) string { return i.AssetPath }),
+	}
+}
+
+// joinItems joins the non-empty field of the Items returned by field with
+// spaces.
+func (r *Record) joinItems(field func(Item) string) string {
+	var list []string
+	for _, i := range r.Items {
+		if v := field(i); v != "" {
+			list = append(list, v)
+		}
+	}
+	return strings.Join(list, " ")
+}
+
+func formatTime(t instagram.Timestamp) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatFloat(f float64) string {
+	if f == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func imageURL(item *instagram.MediaItem) string {
+	if item.Images != nil && item.Images.StandardResolution != nil {
+		return item.Images.StandardResolution.URL
+	}
+	return ""
+}
+
+func videoURL(item *instagram.MediaItem) string {
+	if item.Videos != nil && item.Videos.StandardResolution != nil {
+		return item.Videos.StandardResolution.URL
+	}
+	return ""
+}
+
+// Checkpoint is the progress of an export, saved after every page.
+type Checkpoint struct {
+	UserID string `json:"user_id"`
+	Format Format `json:"format"`
+
+	// MaxID is the next_max_id of the last page written. Empty when the
+	// export is complete.
+	MaxID    string `json:"max_id"`
+	Exported int    `json:"exported"`
+	Done     bool   `json:"done"`
+}
+
+// ErrCheckpointMismatch is returned when the Checkpoint file belongs to an
+// export of another user or in another format.
+var ErrCheckpointMismatch = errors.New("export: checkpoint belongs to another export")
+
+// Exporter exports the media of users.
+type Exporter struct {
+	Client *instagram.Client
+	Format Format
+
+	// Checkpoint is the file progress is saved to. Exports can't be resumed
+	// when it's empty.
+	Checkpoint string
+
+	// Count of media requested per page. The API's default is used when
+	// it's zero.
+	Count int
+
+	// Downloader, if set, downloads the assets of every media, and their
+	// paths are recorded in the Record's Items and AssetPath. Media without
+	// an asset are exported with empty paths.
+	Downloader *download.Downloader
+}
+
+// Export writes the media of the user to w and returns the number of
+// records written by this call. If the Checkpoint file records a finished
+// export, nothing is written.
+func (e *Exporter) Export(ctx context.Context, userID string, w io.Writer) (int, error) {
+	cp, resumed, err := e.loadCheckpoint(userID)
+	if err != nil {
+		return 0, err
+	}
+	if cp.Done {
+		return 0, nil
+	}
+
+	var cw *csv.Writer
+	if e.Format == CSV {
+		cw = csv.NewWriter(w)
+		if !resumed {
+			cw.Write(Columns)
+		}
+	}
+
+	n := 0
+	for {
+		opt := &instagram.UserRecentMediaOptions{Count: e.Count, MaxID: cp.MaxID}
+		media, page, err := e.Client.Users.RecentMediaContext(ctx, userID, opt)
+		if err != nil {
+			return n, err
+		}
+
+		for i := range media {
+			r := NewRecord(&media[i])
+			if e.Downloader != nil {
+				paths, err := e.Downloader.Download(ctx, &media[i])
+				if err != nil && !errors.Is(err, download.ErrNoAsset) {
+					return n, err
+				}
+				for j, p := range paths {
+					r.Items[j].AssetPath = p
+				}
+				r.AssetPath = r.Items[0].AssetPath
+			}
+			if cw != nil {
+				err = cw.Write(r.csv())
+			} else {
+				err = writeJSON(w, r)
+			}
+			if err != nil {
+				return n, err
+			}
+			n++
+		}
+		if cw != nil {
+			cw.Flush()
+			if err := cw.Error(); err != nil {
+				return n, err
+			}
+		}
+
+		cp.Exported += len(media)
+		if page == nil || page.NextMaxID == "" || page.NextMaxID == cp.MaxID || len(media) == 0 {
+			cp.MaxID = ""
+			cp.Done = true
+		} else {
+			cp.MaxID = page.NextMaxID
+		}
+		if err := e.saveCheckpoint(cp); err != nil {
+			return n, err
+		}
+		if cp.Done {
+			return n, nil
+		}
+	}
+}
+
+func writeJSON(w io.Writer, r *Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// loadCheckpoint returns the saved checkpoint, and whether there was one.
+func (e *Exporter) loadCheckpoint(userID string) (*Checkpoint, bool, error) {
+	cp := &Checkpoint{UserID: userID, Format: e.Format}
+	if e.Checkpoint == "" {
+		return cp, false, nil
+	}
+	data, err := ioutil.ReadFile(e.Checkpoint)
+	if os.IsNotExist(err) {
+		return cp, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	saved := new(Checkpoint)
+	if err := json.Unmarshal(data, saved); err != nil {
+		return nil, false, fmt.Errorf("export: checkpoint %v: %v", e.Checkpoint, err)
+	}
+	if saved.UserID != userID || saved.Format != e.Format {
+		return nil, false, ErrCheckpointMismatch
+	}
+	return saved, true, nil
+}
+
+// saveCheckpoint atomically replaces the Checkpoint file with cp.
+func (e *Exporter) saveCheckpoint(cp *Checkpoint) error {
+	if e.Checkpoint == "" {
+		return nil
+	}
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(e.Checkpoint), filepath.Base(e.Checkpoint)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), e.Checkpoint)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
@@ -0,0 +1,235 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gedex/go-instagram/instagram"
+	"github.com/gedex/go-instagram/instagram/download"
+)
+
+// pages are served by newServer, keyed by max_id.
+var pages = map[string]string{
+	"": `{"pagination":{"next_max_id":"2_1"},"data":[
+		{"id":"3_1","type":"image","created_time":"1372176000","user":{"username":"kevin"},
+		 "caption":{"text":"hello, world"},"tags":["a","b"],"likes":{"count":5},
+		 "comments":{"count":1,"data":[{"id":"c","text":"nice","from":{"username":"x"}}]},
+		 "location":{"id":514276,"name":"Shibuya","latitude":35.66,"longitude":139.7},
+		 "images":{"standard_resolution":{"url":"%[1]v/3.jpg","width":640,"height":640}}}]}`,
+	"2_1": `{"pagination":{"next_max_id":"1_1"},"data":[{"id":"2_1","type":"carousel",
+		"carousel_media":[
+			{"type":"image","images":{"standard_resolution":{"url":"%[1]v/a.jpg"}}},
+			{"type":"image","images":{"standard_resolution":{"url":"%[1]v/b.jpg"}}}]}]}`,
+	"1_1": `{"pagination":{},"data":[{"id":"1_1","type":"image"}]}`,
+}
+
+// newServer serves the pages of user 1 and image files. The first request
+// for the max_id fail, if set, gets a server error.
+func newServer(t *testing.T, fail string) (*httptest.Server, *instagram.Client) {
+	failed := false
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".jpg") {
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write([]byte("jpeg"))
+			return
+		}
+		if r.URL.Path != "/users/1/media/recent" {
+			t.Errorf("Unexpected request for %v", r.URL)
+			http.NotFound(w, r)
+			return
+		}
+		maxID := r.URL.Query().Get("max_id")
+		if maxID == fail && fail != "" && !failed {
+			failed = true
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, pages[maxID], server.URL)
+	}))
+
+	client := instagram.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	return server, client
+}
+
+func ids(t *testing.T, ndjson string) []string {
+	var list []string
+	dec := json.NewDecoder(strings.NewReader(ndjson))
+	for dec.More() {
+		var r Record
+		if err := dec.Decode(&r); err != nil {
+			t.Fatalf("Decoding output: %v", err)
+		}
+		list = append(list, r.ID)
+	}
+	return list
+}
+
+func TestExporter_NDJSON(t *testing.T) {
+	server, client := newServer(t, "")
+	defer server.Close()
+
+	var buf bytes.Buffer
+	e := &Exporter{Client: client}
+	n, err := e.Export(context.Background(), "1", &buf)
+	if err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("Export returned %d, want 3", n)
+	}
+
+	var first Record
+	json.Unmarshal([]byte(strings.SplitN(buf.String(), "\n", 2)[0]), &first)
+	want := Record{
+		ID:             "3_1",
+		CreatedTime:    "2013-06-25T16:00:00Z",
+		Type:           instagram.MediaTypeImage,
+		Username:       "kevin",
+		Caption:        "hello, world",
+		Tags:           []string{"a", "b"},
+		Likes:          5,
+		Comments:       1,
+		LocationID:     "514276",
+		LocationName:   "Shibuya",
+		Latitude:       35.66,
+		Longitude:      139.7,
+		ImageURL:       server.URL + "/3.jpg",
+		Items:          []Item{{Type: instagram.MediaTypeImage, ImageURL: server.URL + "/3.jpg"}},
+		RecentComments: []Comment{{ID: "c", Username: "x", Text: "nice"}},
+	}
+	if !reflect.DeepEqual(first, want) {
+		t.Errorf("First record is %+v, want %+v", first, want)
+	}
+	if got, want := ids(t, buf.String()), []string{"3_1", "2_1", "1_1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Exported %v, want %v", got, want)
+	}
+}
+
+func TestExporter_CSV(t *testing.T) {
+	server, client := newServer(t, "")
+	defer server.Close()
+
+	var buf bytes.Buffer
+	e := &Exporter{Client: client, Format: CSV}
+	if _, err := e.Export(context.Background(), "1", &buf); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Reading CSV: %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("CSV has %d rows, want 4", len(rows))
+	}
+	if !reflect.DeepEqual(rows[0], Columns) {
+		t.Errorf("CSV header is %v, want %v", rows[0], Columns)
+	}
+	want := []string{"3_1", "2013-06-25T16:00:00Z", "image", "kevin", "", "hello, world", "a b",
+		"", "5", "1", "514276", "Shibuya", "35.66", "139.7", server.URL + "/3.jpg", "", "",
+		server.URL + "/3.jpg", "", ""}
+	if !reflect.DeepEqual(rows[1], want) {
+		t.Errorf("CSV row is %q, want %q", rows[1], want)
+	}
+	if got, want := rows[2][17], server.URL+"/a.jpg "+server.URL+"/b.jpg"; got != want {
+		t.Errorf("CSV image_urls of a carousel is %q, want %q", got, want)
+	}
+}
+
+func TestExporter_resume(t *testing.T) {
+	server, client := newServer(t, "1_1")
+	defer server.Close()
+
+	dir := t.TempDir()
+	e := &Exporter{Client: client, Format: CSV, Checkpoint: filepath.Join(dir, "checkpoint")}
+
+	var buf bytes.Buffer
+	n, err := e.Export(context.Background(), "1", &buf)
+	if err == nil {
+		t.Fatalf("Export expected error to be returned")
+	}
+	if n != 2 {
+		t.Errorf("Interrupted Export returned %d, want 2", n)
+	}
+
+	if n, err = e.Export(context.Background(), "1", &buf); err != nil {
+		t.Fatalf("Resumed Export returned error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Resumed Export returned %d, want 1", n)
+	}
+
+	rows, _ := csv.NewReader(&buf).ReadAll()
+	var got []string
+	for _, row := range rows {
+		got = append(got, row[0])
+	}
+	if want := []string{"id", "3_1", "2_1", "1_1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Exported %v, want %v", got, want)
+	}
+
+	// The export is complete: running it again writes nothing.
+	if n, err = e.Export(context.Background(), "1", &buf); n != 0 || err != nil {
+		t.Errorf("Completed Export returned %d, %v, want 0, nil", n, err)
+	}
+
+	e.Format = NDJSON
+	if _, err := e.Export(context.Background(), "1", &buf); err != ErrCheckpointMismatch {
+		t.Errorf("Export returned error %v, want %v", err, ErrCheckpointMismatch)
+	}
+}
+
+func TestExporter_download(t *testing.T) {
+	server, client := newServer(t, "")
+	defer server.Close()
+
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	e := &Exporter{Client: client, Downloader: &download.Downloader{Dir: dir}}
+	if _, err := e.Export(context.Background(), "1", &buf); err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var r, carousel, noAsset Record
+	json.Unmarshal([]byte(lines[0]), &r)
+	json.Unmarshal([]byte(lines[1]), &carousel)
+	json.Unmarshal([]byte(lines[2]), &noAsset)
+	if noAsset.AssetPath != "" {
+		t.Errorf("Record.AssetPath of a media without images is %q, want empty", noAsset.AssetPath)
+	}
+	if r.AssetPath == "" {
+		t.Fatalf("Record.AssetPath is empty")
+	}
+	if data, err := os.ReadFile(r.AssetPath); err != nil || string(data) != "jpeg" {
+		t.Errorf("Downloaded file holds %q, %v, want jpeg", data, err)
+	}
+
+	// Every item of a carousel is downloaded.
+	if len(carousel.Items) != 2 || carousel.AssetPath != carousel.Items[0].AssetPath {
+		t.Fatalf("Carousel record has items %+v and AssetPath %q", carousel.Items, carousel.AssetPath)
+	}
+	for _, item := range carousel.Items {
+		if data, err := os.ReadFile(item.AssetPath); err != nil || string(data) != "jpeg" {
+			t.Errorf("Downloaded file %q holds %q, %v, want jpeg", item.AssetPath, data, err)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package feedsync fetches the media added to feeds and tags since the last
+time they were polled.
+
+	s := &feedsync.Syncer{Store: feedsync.NewFileStore("sync.json")}
+	n, err := s.Sync(ctx, feedsync.TagRecentMedia(client, "golang"), func(m *instagram.Media) error {
+		fmt.Println(m.ID)
+		return nil
+	})
+
+Each Source has a high-water mark, the min_id or min_tag_id of the newest
+page seen, which is persisted in the Store together with the IDs of the
+media emitted recently. A Sync only requests media newer than the mark,
+skips those already emitted, and emits the rest oldest first.
+*/
+package feedsync
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/gedex/go-instagram/instagram"
+)
+
+// A Source is a list of media that can be polled for new items.
+type Source interface {
+	// Key identifies the Source in a Store.
+	Key() string
+
+	// Fetch returns the media newer than the high-water mark minID, newest
+	// first, along with the new mark. An empty minID means the Source was
+	// never synced, in which case only the first page is returned.
+	Fetch(ctx context.Context, minID string) ([]instagram.Media, string, error)
+}
+
+type mediaFeed struct {
+	client *instagram.Client
+}
+
+// MediaFeed returns the Source of the authenticated user's feed.
+func MediaFeed(c *instagram.Client) Source {
+	return &mediaFeed{c}
+}
+
+func (s *mediaFeed) Key() string { return "feed" }
+
+func (s *mediaFeed) Fetch(ctx context.Context, minID string) ([]instagram.Media, string, error) {
+	it := s.client.Users.MediaFeedIterator(ctx, &instagram.MediaFeedOptions{MinID: minID})
+	// Also stop at the mark, in case the API ignores min_id. Media IDs grow
+	// with time, so the mark bounds the walk even if its media was deleted.
+	passed := false
+	media, err := collect(it, minID, func(m *instagram.Media) bool {
+		passed = passed || m.ID == minID
+		return passed || atOrBelow(mediaNumber(m.ID), mediaNumber(minID))
+	}, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	// The feed has no pagination cursor for newer media: the newest media
+	// itself is the mark.
+	if len(media) > 0 {
+		minID = media[0].ID
+	}
+	return media, minID, nil
+}
+
+type tagRecentMedia struct {
+	client *instagram.Client
+	tag    string
+}
+
+// TagRecentMedia returns the Source of the media recently tagged with tag.
+func TagRecentMedia(c *instagram.Client, tag string) Source {
+	return &tagRecentMedia{c, tag}
+}
+
+func (s *tagRecentMedia) Key() string { return "tag:" + s.tag }
+
+func (s *tagRecentMedia) Fetch(ctx context.Context, minID string) ([]instagram.Media, string, error) {
+	opt := &instagram.TagRecentMediaOptions{MinTagID: minID}
+	it := s.client.Tags.RecentMediaIterator(ctx, s.tag, opt)
+	var mark string
+	media, err := collect(it, minID, nil, func(p *instagram.ResponsePagination) bool {
+		if mark == "" {
+			mark = p.MinTagID
+		}
+		// Pages go back in time: once the next one starts at or below the
+		// mark, the rest was synced already. This bounds the walk when the
+		// API's next_url drops min_tag_id.
+		return minID == "" || p.NextMaxTagID == "" || !atOrBelow(p.NextMaxTagID, minID)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if mark == "" {
+		mark = minID
+	}
+	return media, mark, nil
+}
+
+// collect returns the items of it, only those of the first page if minID is
+// empty. If synced isn't nil, the media for which it returns true are
+// dropped, and the page they're on is the last one. If onPage isn't nil, it's
+// called with the pagination of every page, and the page is the last one
+// unless it returns true.
+func collect(it *instagram.Iterator[instagram.Media], minID string, synced func(*instagram.Media) bool, onPage func(*instagram.ResponsePagination) bool) ([]instagram.Media, error) {
+	if minID == "" {
+		it.MaxPages = 1
+	}
+	var media []instagram.Media
+	var last *instagram.ResponsePagination
+	pages := 0
+	for it.Next() {
+		if p := it.Pagination(); p != last {
+			pages++
+			if onPage != nil && !onPage(p) {
+				it.MaxPages = pages
+			}
+		}
+		last = it.Pagination()
+		m := it.Value()
+		if synced != nil && synced(&m) {
+			it.MaxPages = pages
+			continue
+		}
+		media = append(media, m)
+	}
+	return media, it.Err()
+}
+
+// atOrBelow reports whether the numeric ID id is lower than or equal to
+// mark. IDs that aren't numbers are never.
+func atOrBelow(id, mark string) bool {
+	if !isNumber(id) || !isNumber(mark) {
+		return false
+	}
+	id, mark = strings.TrimLeft(id, "0"), strings.TrimLeft(mark, "0")
+	if len(id) != len(mark) {
+		return len(id) < len(mark)
+	}
+	return id <= mark
+}
+
+// mediaNumber returns the numeric part of the media ID id, which is followed
+// by the ID of its owner.
+func mediaNumber(id string) string {
+	if i := strings.IndexByte(id, '_'); i >= 0 {
+		return id[:i]
+	}
+	return id
+}
+
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// State is what a Store remembers of a Source.
+type State struct {
+	// MinID is the high-water mark returned by Source.Fetch.
+	MinID string `json:"min_id"`
+
+	// Seen holds the IDs of the media emitted most recently, oldest first.
+	Seen []string `json:"seen"`
+}
+
+// A Store persists the State of Sources. Load returns nil, and no error,
+// for a Source that was never saved.
+type Store interface {
+	Load(key string) (*State, error)
+	Save(key string, s *State) error
+}
+
+// DefaultSeenLimit is the number of media IDs remembered per Source when
+// Syncer.SeenLimit is zero.
+const DefaultSeenLimit = 1000
+
+// Syncer polls Sources for new media.
+type Syncer struct {
+	Store Store
+
+	// SeenLimit is the number of media IDs remembered per Source to
+	// deduplicate media returned again by the API. DefaultSeenLimit is used
+	// when it's zero.
+	SeenLimit int
+}
+
+// Sync fetches the media added to src since the last Sync and calls emit
+// with each of them, oldest first. It returns the number of media emitted.
+//
+// If emit returns an error, Sync stops and returns it. The media emitted so
+// far are remembered, while the high-water mark is left unchanged, so the
+// next Sync emits the remaining media again.
+func (s *Syncer) Sync(ctx context.Context, src Source, emit func(*instagram.Media) error) (int, error) {
+	key := src.Key()
+	state, err := s.Store.Load(key)
+	if err != nil {
+		return 0, err
+	}
+	if state == nil {
+		state = new(State)
+	}
+
+	media, mark, err := src.Fetch(ctx, state.MinID)
+	if err != nil {
+		return 0, err
+	}
+
+	seen := make(map[string]bool, len(state.Seen))
+	for _, id := range state.Seen {
+		seen[id] = true
+	}
+	var fresh []*instagram.Media
+	for i := len(media) - 1; i >= 0; i-- {
+		m := &media[i]
+		if !seen[m.ID] {
+			seen[m.ID] = true
+			fresh = append(fresh, m)
+		}
+	}
+	// The API lists media newest first; reversing gives the chronological
+	// order of most of them, and the stable sort fixes the rest.
+	sort.SliceStable(fresh, func(i, j int) bool {
+		return fresh[i].CreatedTime.Before(fresh[j].CreatedTime.Time)
+	})
+
+	n := 0
+	for _, m := range fresh {
+		if err := emit(m); err != nil {
+			s.remember(state, fresh[:n])
+			if serr := s.Store.Save(key, state); serr != nil {
+				return n, serr
+			}
+			return n, err
+		}
+		n++
+	}
+
+	s.remember(state, fresh)
+	state.MinID = mark
+	return n, s.Store.Save(key, state)
+}
+
+// remember adds the IDs of media to state.Seen, dropping the oldest ones
+// beyond the SeenLimit.
+func (s *Syncer) remember(state *State, media []*instagram.Media) {
+	for _, m := range media {
+		state.Seen = append(state.Seen, m.ID)
+	}
+	limit := s.SeenLimit
+	if limit <= 0 {
+		limit = DefaultSeenLimit
+	}
+	if len(state.Seen) > limit {
+		state.Seen = append([]string(nil), state.Seen[len(state.Seen)-limit:]...)
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package feedsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gedex/go-instagram/instagram"
+)
+
+// newClient returns a Client talking to a server using mux.
+func newClient(t *testing.T, mux *http.ServeMux) *instagram.Client {
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	client := instagram.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	return client
+}
+
+// tagPages are the responses of tags/go/media/recent, keyed by min_tag_id.
+var tagPages = map[string]string{
+	"": `{"pagination":{"min_tag_id":"100"},"data":[
+		{"id":"3","created_time":"300"},{"id":"2","created_time":"200"}]}`,
+	"100": `{"pagination":{"min_tag_id":"200"},"data":[
+		{"id":"5","created_time":"500"},{"id":"4","created_time":"400"},{"id":"3","created_time":"300"}]}`,
+	"200": `{"pagination":{},"data":[]}`,
+}
+
+func syncIDs(t *testing.T, s *Syncer, src Source) []string {
+	var ids []string
+	_, err := s.Sync(context.Background(), src, func(m *instagram.Media) error {
+		ids = append(ids, m.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+	return ids
+}
+
+func TestSyncer_tag(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tags/go/media/recent", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, tagPages[r.URL.Query().Get("min_tag_id")])
+	})
+	src := TagRecentMedia(newClient(t, mux), "go")
+	s := &Syncer{Store: NewMemoryStore()}
+
+	for i, want := range [][]string{{"2", "3"}, {"4", "5"}, nil} {
+		if got := syncIDs(t, s, src); !reflect.DeepEqual(got, want) {
+			t.Errorf("Sync %d emitted %v, want %v", i, got, want)
+		}
+	}
+
+	st, _ := s.Store.Load("tag:go")
+	if want := (&State{MinID: "200", Seen: []string{"2", "3", "4", "5"}}); !reflect.DeepEqual(st, want) {
+		t.Errorf("Saved State is %+v, want %+v", st, want)
+	}
+}
+
+func TestSyncer_tagPages(t *testing.T) {
+	// The next_url of the pages drops min_tag_id, as the API may do.
+	var requested []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tags/go/media/recent", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		requested = append(requested, q.Get("max_tag_id"))
+		switch {
+		case q.Get("min_tag_id") == "" && q.Get("max_tag_id") == "":
+			fmt.Fprintf(w, `{"pagination":{"min_tag_id":"100","next_max_tag_id":"50","next_url":"http://%v/tags/go/media/recent?max_tag_id=50"},
+				"data":[{"id":"3","created_time":"300"},{"id":"2","created_time":"200"}]}`, r.Host)
+		case q.Get("max_tag_id") == "":
+			fmt.Fprintf(w, `{"pagination":{"min_tag_id":"300","next_max_tag_id":"200","next_url":"http://%v/tags/go/media/recent?max_tag_id=200"},
+				"data":[{"id":"7","created_time":"700"},{"id":"6","created_time":"600"}]}`, r.Host)
+		case q.Get("max_tag_id") == "200":
+			fmt.Fprintf(w, `{"pagination":{"next_max_tag_id":"100","next_url":"http://%v/tags/go/media/recent?max_tag_id=100"},
+				"data":[{"id":"5","created_time":"500"},{"id":"4","created_time":"400"}]}`, r.Host)
+		default:
+			fmt.Fprintf(w, `{"pagination":{"next_max_tag_id":"50","next_url":"http://%v/tags/go/media/recent?max_tag_id=50"},
+				"data":[{"id":"3","created_time":"300"},{"id":"2","created_time":"200"}]}`, r.Host)
+		}
+	})
+	src := TagRecentMedia(newClient(t, mux), "go")
+	s := &Syncer{Store: NewMemoryStore()}
+
+	for i, want := range [][]string{{"2", "3"}, {"4", "5", "6", "7"}} {
+		if got := syncIDs(t, s, src); !reflect.DeepEqual(got, want) {
+			t.Errorf("Sync %d emitted %v, want %v", i, got, want)
+		}
+	}
+	// The pages below the mark of the first Sync aren't requested.
+	if want := []string{"", "", "200"}; !reflect.DeepEqual(requested, want) {
+		t.Errorf("Sync requested max_tag_id %q, want %q", requested, want)
+	}
+	if st, _ := s.Store.Load("tag:go"); st.MinID != "300" {
+		t.Errorf("Saved MinID is %q, want 300", st.MinID)
+	}
+}
+
+func TestSyncer_feed(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/self/feed", func(w http.ResponseWriter, r *http.Request) {
+		// Ignore min_id, which makes the Source stop at the mark.
+		if r.URL.Query().Get("page") == "" {
+			fmt.Fprintf(w, `{"pagination":{"next_url":"http://%v/users/self/feed?page=2"},"data":[{"id":"c"},{"id":"b"}]}`, r.Host)
+			return
+		}
+		fmt.Fprint(w, `{"data":[{"id":"a"}]}`)
+	})
+	src := MediaFeed(newClient(t, mux))
+	store := NewMemoryStore()
+	store.Save("feed", &State{MinID: "b"})
+	s := &Syncer{Store: store}
+
+	if got, want := syncIDs(t, s, src), []string{"c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Sync emitted %v, want %v", got, want)
+	}
+	if st, _ := store.Load("feed"); st.MinID != "c" {
+		t.Errorf("Saved MinID is %q, want c", st.MinID)
+	}
+}
+
+func TestSyncer_feedMarkDeleted(t *testing.T) {
+	var pages []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/self/feed", func(w http.ResponseWriter, r *http.Request) {
+		// Ignore min_id, and no longer list the marked media 30_1.
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		switch page {
+		case "":
+			fmt.Fprintf(w, `{"pagination":{"next_url":"http://%v/users/self/feed?page=2"},"data":[{"id":"50_1"},{"id":"40_1"}]}`, r.Host)
+		case "2":
+			fmt.Fprintf(w, `{"pagination":{"next_url":"http://%v/users/self/feed?page=3"},"data":[{"id":"35_1"},{"id":"20_1"}]}`, r.Host)
+		default:
+			fmt.Fprint(w, `{"data":[{"id":"10_1"}]}`)
+		}
+	})
+	src := MediaFeed(newClient(t, mux))
+	store := NewMemoryStore()
+	store.Save("feed", &State{MinID: "30_1"})
+	s := &Syncer{Store: store}
+
+	if got, want := syncIDs(t, s, src), []string{"35_1", "40_1", "50_1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Sync emitted %v, want %v", got, want)
+	}
+	if want := []string{"", "2"}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("Sync requested pages %q, want %q", pages, want)
+	}
+}
+
+func TestSyncer_emitError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tags/go/media/recent", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, tagPages[r.URL.Query().Get("min_tag_id")])
+	})
+	src := TagRecentMedia(newClient(t, mux), "go")
+	s := &Syncer{Store: NewMemoryStore()}
+
+	errStop := errors.New("stop")
+	n, err := s.Sync(context.Background(), src, func(m *instagram.Media) error {
+		if m.ID == "3" {
+			return errStop
+		}
+		return nil
+	})
+	if n != 1 || err != errStop {
+		t.Fatalf("Sync returned %d, %v, want 1, %v", n, err, errStop)
+	}
+
+	// The next Sync starts over from the same mark and skips media 2.
+	if got, want := syncIDs(t, s, src), []string{"3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Sync emitted %v, want %v", got, want)
+	}
+}
+
+func TestSyncer_SeenLimit(t *testing.T) {
+	s := &Syncer{SeenLimit: 2}
+	st := &State{Seen: []string{"1", "2"}}
+	s.remember(st, []*instagram.Media{{ID: "3"}})
+	if want := []string{"2", "3"}; !reflect.DeepEqual(st.Seen, want) {
+		t.Errorf("State.Seen = %v, want %v", st.Seen, want)
+	}
+}
+
+// mapKV is a KV backed by a map.
+type mapKV map[string][]byte
+
+func (kv mapKV) Get(key []byte) ([]byte, error) { return kv[string(key)], nil }
+func (kv mapKV) Put(key, value []byte) error    { kv[string(key)] = value; return nil }
+
+func TestStores(t *testing.T) {
+	stores := map[string]Store{
+		"MemoryStore": NewMemoryStore(),
+		"FileStore":   NewFileStore(filepath.Join(t.TempDir(), "sync.json")),
+		"KVStore":     &KVStore{KV: mapKV{}},
+	}
+	for name, store := range stores {
+		if st, err := store.Load("k"); st != nil || err != nil {
+			t.Errorf("%v.Load of a missing key returned %+v, %v", name, st, err)
+		}
+		want := &State{MinID: "1", Seen: []string{"a", "b"}}
+		if err := store.Save("k", want); err != nil {
+			t.Errorf("%v.Save returned error: %v", name, err)
+		}
+		store.Save("other", &State{MinID: "2"})
+		if st, err := store.Load("k"); err != nil || !reflect.DeepEqual(st, want) {
+			t.Errorf("%v.Load returned %+v, %v, want %+v", name, st, err, want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package feedsync
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// MemoryStore is a Store keeping States in memory, for programs that sync
+// in a loop without restarting.
+type MemoryStore struct {
+	mu     sync.Mutex
+	states map[string]State
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{states: make(map[string]State)}
+}
+
+// Load returns a copy of the State saved under key.
+func (s *MemoryStore) Load(key string) (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.states[key]
+	if !ok {
+		return nil, nil
+	}
+	st.Seen = append([]string(nil), st.Seen...)
+	return &st, nil
+}
+
+// Save stores a copy of st under key.
+func (s *MemoryStore) Save(key string, st *State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := *st
+	c.Seen = append([]string(nil), st.Seen...)
+	s.states[key] = c
+	return nil
+}
+
+// FileStore is a Store keeping the States of all Sources in a single JSON
+// file, which is rewritten atomically on every Save.
+type FileStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// NewFileStore returns a FileStore using the file at path, which is created
+// on the first Save.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{Path: path}
+}
+
+func (s *FileStore) read() (map[string]*State, error) {
+	states := make(map[string]*State)
+	data, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return states, json.Unmarshal(data, &states)
+}
+
+// Load returns the State saved under key.
+func (s *FileStore) Load(key string) (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	states, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return states[key], nil
+}
+
+// Save stores st under key.
+func (s *FileStore) Save(key string, st *State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	states, err := s.read()
+	if err != nil {
+		return err
+	}
+	states[key] = st
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(s.Path, data)
+}
+
+// writeFile replaces the file at path with data, through a temporary file
+// so that readers never see a partial write.
+func writeFile(path string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// KV is a byte-oriented key-value store, such as a BoltDB bucket wrapped in
+// a transaction. Get returns nil for a missing key.
+type KV interface {
+	Get(key []byte) ([]byte, error)
+	Put(key, value []byte) error
+}
+
+// KVStore is a Store saving each State as a JSON value of a KV.
+type KVStore struct {
+	KV KV
+}
+
+// Load returns the State saved under key.
+func (s *KVStore) Load(key string) (*State, error) {
+	data, err := s.KV.Get([]byte(key))
+	if err != nil || data == nil {
+		return nil, err
+	}
+	st := new(State)
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// Save stores st under key.
+func (s *KVStore) Save(key string, st *State) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	return s.KV.Put([]byte(key), data)
+}
@@ -6,9 +6,8 @@
 package instagram
 
 import (
+	"context"
 	"fmt"
-	"net/url"
-	"strconv"
 )
 
 // GeographiesService handles communication with the geographies related
@@ -19,38 +18,67 @@ type GeographiesService struct {
 	client *Client
 }
 
+// GeographyRecentMediaOptions specifies the optional parameters to
+// GeographiesService.RecentMediaContext.
+type GeographyRecentMediaOptions struct {
+	// Count of media to return.
+	Count int `url:"count,omitempty"`
+
+	// Return media later than MinID.
+	MinID string `url:"min_id,omitempty"`
+
+	legacyOptions
+}
+
+// Validate returns an error if any option is out of range.
+func (o *GeographyRecentMediaOptions) Validate() error {
+	return validateCount(o.Count)
+}
+
 // RecentMedia gets recent media from a geography subscription that created by
-// real-time subscriptions.
+// real-time subscriptions (see SubscriptionsService.Create).
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/geographies/#get_geographies_media_recent
 func (s *GeographiesService) RecentMedia(geoId string, opt *Parameters) ([]Media, *ResponsePagination, error) {
+	return s.RecentMediaContext(context.Background(), geoId, opt.geographyRecentMediaOptions())
+}
+
+// RecentMediaContext is like RecentMedia but takes a context that controls
+// the request, and options specific to this endpoint.
+func (s *GeographiesService) RecentMediaContext(ctx context.Context, geoId string, opt *GeographyRecentMediaOptions) ([]Media, *ResponsePagination, error) {
 	u := fmt.Sprintf("geographies/%v/media/recent", geoId)
-	if opt != nil {
-		params := url.Values{}
-		if opt.MinID != "" {
-			params.Add("min_id", opt.MinID)
-		}
-		if opt.Count != 0 {
-			params.Add("count", strconv.FormatUint(opt.Count, 10))
+	if opt != nil && !opt.legacy {
+		if err := opt.Validate(); err != nil {
+			return nil, nil, err
 		}
-		u += "?" + params.Encode()
+	}
+	u, err := addOptions(u, opt)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", u, "")
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
 	if err != nil {
 		return nil, nil, err
 	}
 
 	media := new([]Media)
-	_, err = s.client.Do(req, media)
+	resp, err := s.client.Do(req, media)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	page := new(ResponsePagination)
-	if s.client.Response.Pagination != nil {
-		page = s.client.Response.Pagination
+	if resp.Pagination != nil {
+		page = resp.Pagination
 	}
 
 	return *media, page, err
 }
+
+// RecentMediaIterator returns an Iterator over all pages of the media from a geography subscription.
+func (s *GeographiesService) RecentMediaIterator(ctx context.Context, geoId string, opt *GeographyRecentMediaOptions) *Iterator[Media] {
+	return newIterator(ctx, s.client, func(ctx context.Context) ([]Media, *ResponsePagination, error) {
+		return s.RecentMediaContext(ctx, geoId, opt)
+	})
+}
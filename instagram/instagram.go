@@ -27,18 +27,38 @@ Set optional parameters for an API method by passing an Parameters object.
 	opt := &instagram.Parameters{Count: 3}
 	media, next, err := client.Users.RecentMedia("3", opt)
 
+Every method has a Context variant which accepts a context.Context as its
+first argument. The context is attached to the underlying HTTP request, so
+it can be used to set deadlines or to cancel in-flight calls:
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	media, next, err := client.Users.RecentMediaContext(ctx, "3", nil)
+
+The Context variants take an options type specific to their endpoint rather
+than Parameters. Options are validated before the request is sent:
+
+	opt := &instagram.UserRecentMediaOptions{Count: 3}
+	media, next, err := client.Users.RecentMediaContext(ctx, "3", opt)
+
+A Client is safe for concurrent use by multiple goroutines. Per-call envelope
+information such as meta and pagination is returned by Client.Do rather than
+stored on the Client.
+
 The full Instagram API is documented at http://instagram.com/developer/endpoints/.
 */
 package instagram
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 const (
@@ -48,6 +68,9 @@ const (
 	// BaseURL represents Instagram API base URL
 	BaseURL = "https://api.instagram.com/v1/"
 
+	// OEmbedURL represents Instagram oEmbed endpoint URL
+	OEmbedURL = "https://api.instagram.com/oembed"
+
 	// UserAgent represents this client User-Agent
 	UserAgent = "github.com/gedex/go-instagram v" + LibraryVersion
 )
@@ -60,6 +83,9 @@ type Client struct {
 	// Base URL for API requests.
 	BaseURL *url.URL
 
+	// URL of the oEmbed endpoint, used by OEmbed.
+	OEmbedURL *url.URL
+
 	// UserAgent agent used when communicating with Instagram API.
 	UserAgent string
 
@@ -72,6 +98,12 @@ type Client struct {
 	// Authenticated user's access_token
 	AccessToken string
 
+	// EnforceSignedRequests adds the sig parameter, computed with
+	// ClientSecret, to every request, as required by applications with
+	// "Enforce signed requests" enabled. The client_secret itself is then
+	// never sent in the query string.
+	EnforceSignedRequests bool
+
 	// Services used for talking to different parts of the API.
 	Users         *UsersService
 	Relationships *RelationshipsService
@@ -81,12 +113,43 @@ type Client struct {
 	Tags          *TagsService
 	Locations     *LocationsService
 	Geographies   *GeographiesService
+	Subscriptions *SubscriptionsService
+	OEmbed        *OEmbedService
+
+	// RatelimitPolicy controls what Do does once the API reported that the
+	// current token has no calls remaining.
+	RatelimitPolicy RatelimitPolicy
+
+	// Limiter, if set, is waited on before every request is sent.
+	Limiter Limiter
+
+	// RetryPolicy, if set, controls how failed requests are retried. No
+	// retries are made when it's nil.
+	RetryPolicy *RetryPolicy
+
+	// Cache, if set, stores the responses of GET requests for which CacheTTL
+	// returns a positive duration. Stale entries are revalidated with
+	// conditional requests when the API sent an ETag or Last-Modified header.
+	Cache Cache
+
+	// CacheTTL returns how long responses of an endpoint, given as a path
+	// such as "/users/3", stay fresh in Cache. DefaultCacheTTL is used when
+	// it's nil.
+	CacheTTL func(endpoint string) time.Duration
 
-	// Temporary Response
-	Response *Response
+	// Latest rate limits reported by the API, per token.
+	ratelimits ratelimitTracker
+
+	cacheStats cacheCounters
 }
 
 // Parameters specifies the optional parameters to various service's methods.
+//
+// Parameters is kept for compatibility: every method taking it silently
+// ignores the fields its endpoint doesn't support. The Context variants of
+// those methods take an options type specific to their endpoint instead,
+// such as UserRecentMediaOptions or MediaSearchOptions, which is validated
+// before the request is sent.
 type Parameters struct {
 	Count        uint64
 	MinID        string
@@ -96,6 +159,7 @@ type Parameters struct {
 	Lat          float64
 	Lng          float64
 	Distance     float64
+	Cursor       string
 }
 
 // Ratelimit specifies API calls limit found in HTTP headers.
@@ -129,10 +193,12 @@ func (r *Response) GetData() interface{} {
 	return &r.Data
 }
 
-// GetError gets error from meta's response.
+// GetError gets error from meta's response. The returned error is an
+// *ErrorResponse, so it can be matched against the Err* values with
+// errors.Is.
 func (r *Response) GetError() error {
-	if r.Meta.ErrorType != "" || r.Meta.ErrorMessage != "" {
-		return fmt.Errorf(fmt.Sprintf("%s: %s", r.Meta.ErrorType, r.Meta.ErrorMessage))
+	if r.Meta != nil && (r.Meta.ErrorType != "" || r.Meta.ErrorMessage != "") {
+		return (*ErrorResponse)(r)
 	}
 	return nil
 }
@@ -142,7 +208,7 @@ func (r *Response) GetPagination() *ResponsePagination {
 	return r.Pagination
 }
 
-// Parsed rate limit information from response headers.
+// GetRatelimit parses rate limit information from response headers.
 func (r *Response) GetRatelimit() (Ratelimit, error) {
 	var rl Ratelimit
 	var err error
@@ -163,12 +229,18 @@ func (r *Response) GetRatelimit() (Ratelimit, error) {
 // NextURL gets next url which represents URL for next set of data.
 func (r *Response) NextURL() string {
 	p := r.GetPagination()
+	if p == nil {
+		return ""
+	}
 	return p.NextURL
 }
 
 // NextMaxID gets MaxID parameter that can be passed for next request.
 func (r *Response) NextMaxID() string {
 	p := r.GetPagination()
+	if p == nil {
+		return ""
+	}
 	return p.NextMaxID
 }
 
@@ -186,6 +258,15 @@ type ResponseMeta struct {
 type ResponsePagination struct {
 	NextURL   string `json:"next_url,omitempty"`
 	NextMaxID string `json:"next_max_id,omitempty"`
+
+	// Tag endpoints page by tag ID: MinTagID is the newest tag ID of the
+	// page, to be passed as min_tag_id later on to only get newer media.
+	MinTagID     string `json:"min_tag_id,omitempty"`
+	NextMaxTagID string `json:"next_max_tag_id,omitempty"`
+
+	// Relationship lists page by cursor: NextCursor is passed as cursor to
+	// get the next page.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // NewClient returns a new Instagram API client. if a nil httpClient is
@@ -195,10 +276,12 @@ func NewClient(httpClient *http.Client) *Client {
 		httpClient = http.DefaultClient
 	}
 	baseURL, _ := url.Parse(BaseURL)
+	oembedURL, _ := url.Parse(OEmbedURL)
 
 	c := &Client{
 		client:    httpClient,
 		BaseURL:   baseURL,
+		OEmbedURL: oembedURL,
 		UserAgent: UserAgent,
 	}
 	c.Users = &UsersService{client: c}
@@ -209,6 +292,8 @@ func NewClient(httpClient *http.Client) *Client {
 	c.Tags = &TagsService{client: c}
 	c.Locations = &LocationsService{client: c}
 	c.Geographies = &GeographiesService{client: c}
+	c.Subscriptions = &SubscriptionsService{client: c}
+	c.OEmbed = &OEmbedService{client: c}
 
 	return c
 }
@@ -218,11 +303,23 @@ func NewClient(httpClient *http.Client) *Client {
 // Relative URLs should always be specified without a preceding slash. If
 // specified
 func (c *Client) NewRequest(method, urlStr string, body string) (*http.Request, error) {
+	return c.NewRequestContext(context.Background(), method, urlStr, body)
+}
+
+// NewRequestContext is like NewRequest but attaches ctx to the returned
+// request, so the request is aborted when ctx is canceled or its deadline
+// expires.
+func (c *Client) NewRequestContext(ctx context.Context, method, urlStr string, body string) (*http.Request, error) {
 	rel, err := url.Parse(urlStr)
 	if err != nil {
 		return nil, err
 	}
 
+	form, err := url.ParseQuery(body)
+	if err != nil {
+		return nil, err
+	}
+
 	u := c.BaseURL.ResolveReference(rel)
 	q := u.Query()
 	if c.AccessToken != "" && q.Get("access_token") == "" {
@@ -231,12 +328,17 @@ func (c *Client) NewRequest(method, urlStr string, body string) (*http.Request,
 	if c.ClientID != "" && q.Get("client_id") == "" {
 		q.Set("client_id", c.ClientID)
 	}
-	if c.ClientSecret != "" && q.Get("client_secret") == "" {
+	if c.EnforceSignedRequests {
+		// The secret signs the request instead of being sent along. A sig
+		// carried over from a pagination next_url is stale.
+		q.Del("sig")
+		q.Set("sig", GenerateSig(c.endpoint(u), mergeValues(q, form), c.ClientSecret))
+	} else if c.ClientSecret != "" && q.Get("client_secret") == "" && form.Get("client_secret") == "" {
 		q.Set("client_secret", c.ClientSecret)
 	}
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequest(method, u.String(), bytes.NewBufferString(body))
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewBufferString(body))
 	if err != nil {
 		return nil, err
 	}
@@ -252,36 +354,98 @@ func (c *Client) NewRequest(method, urlStr string, body string) (*http.Request,
 // Do sends an API request and returns the API response. The API response is
 // decoded and stored in the value pointed to by v, or returned as an error if
 // an API error has occurred.
-func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
-	resp, err := c.client.Do(req)
+//
+// The returned Response carries the envelope's meta and pagination of this
+// particular call, so a Client may be shared by multiple goroutines.
+//
+// The request's context is honored: if it is canceled or its deadline
+// expires before the response is received, the context's error is returned.
+//
+// Before sending, Do waits on the Client's Limiter and applies its
+// RatelimitPolicy. Rate limit headers of every response are recorded and
+// available through Ratelimit. Failed attempts are retried according to the
+// Client's RetryPolicy.
+func (c *Client) Do(req *http.Request, v interface{}) (*Response, error) {
+	var resp *http.Response
+	var err error
+	if ttl := c.cacheTTL(req); ttl > 0 {
+		resp, err = c.sendCached(req, ttl)
+	} else {
+		resp, err = c.send(req)
+	}
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	r := &Response{Response: resp}
 	err = CheckResponse(resp)
 	if err != nil {
-		return resp, err
+		return r, err
 	}
 
-	r := &Response{Response: resp}
 	if v != nil {
 		r.Data = v
 		err = json.NewDecoder(resp.Body).Decode(r)
-		c.Response = r
 	}
-	return resp, err
+	return r, err
 }
 
-// ErrorResponse represents a Response which contains an error
+// ErrorResponse represents a Response which contains an error.
+//
+// The kind of error is found in Meta.ErrorType; use errors.Is with one of the
+// Err* values to test for it:
+//
+//	if errors.Is(err, instagram.ErrAPINotAllowed) {
+//		// private user, skip it
+//	}
 type ErrorResponse Response
 
 func (r *ErrorResponse) Error() string {
+	if r.Response == nil || r.Response.Request == nil {
+		return fmt.Sprintf("%v: %v", r.Meta.ErrorType, r.Meta.ErrorMessage)
+	}
 	return fmt.Sprintf("%v %v: %d %v",
 		r.Response.Request.Method, r.Response.Request.URL,
 		r.Response.StatusCode, r.Meta.ErrorMessage)
 }
 
+// Is reports whether target is the Err* value matching the response's
+// error type.
+func (r *ErrorResponse) Is(target error) bool {
+	if r.Meta == nil {
+		return false
+	}
+	err, ok := errorTypes[r.Meta.ErrorType]
+	if !ok {
+		switch r.Meta.Code {
+		case http.StatusTooManyRequests:
+			err = ErrOAuthRateLimit
+		case http.StatusNotFound:
+			err = ErrAPINotFound
+		}
+	}
+	return err != nil && err == target
+}
+
+// sendOnce sends req a single time, honoring rate limits.
+func (c *Client) sendOnce(req *http.Request) (*http.Response, error) {
+	if err := c.checkRatelimit(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+
+	c.trackRatelimit(resp)
+	return resp, nil
+}
+
 // CheckResponse checks the API response for error, and returns it
 // if present. A response is considered an error if it has non StatusOK
 // code.
@@ -298,7 +462,7 @@ func CheckResponse(r *http.Response) error {
 		ErrorMessage: http.StatusText(r.StatusCode),
 	}
 
-	if r.StatusCode == http.StatusInternalServerError || r.StatusCode == http.StatusNotFound {
+	if r.StatusCode == http.StatusInternalServerError {
 		return resp
 	}
 
@@ -306,7 +470,9 @@ func CheckResponse(r *http.Response) error {
 	if err != nil {
 		resp.Meta.ErrorMessage = err.Error()
 	}
-	if err := json.Unmarshal(data, resp); err != nil {
+	// A 404 may come from outside the API, with a body that isn't JSON;
+	// the status text is the better message then.
+	if err := json.Unmarshal(data, resp); err != nil && r.StatusCode != http.StatusNotFound {
 		resp.Meta.ErrorMessage = err.Error()
 	}
 
@@ -6,10 +6,16 @@
 package instagram
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 )
 
 var (
@@ -87,3 +93,146 @@ func TestNewRequest(t *testing.T) {
 		t.Errorf("NewRequest() User-Agent = %v, want %v", userAgent, c.UserAgent)
 	}
 }
+
+func TestNewRequestContext(t *testing.T) {
+	c := NewClient(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := c.NewRequestContext(ctx, "GET", "foo", "")
+	if err != nil {
+		t.Fatalf("NewRequestContext returned error: %v", err)
+	}
+	if req.Context() != ctx {
+		t.Errorf("NewRequestContext() context = %v, want %v", req.Context(), ctx)
+	}
+}
+
+func TestDo_contextCanceled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Request sent with a canceled context")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, _ := client.NewRequestContext(ctx, "GET", "/", "")
+	_, err := client.Do(req, nil)
+	if err != context.Canceled {
+		t.Errorf("Do returned error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestDo_contextDeadline(t *testing.T) {
+	setup()
+	defer teardown()
+
+	done := make(chan struct{})
+	defer close(done)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	req, _ := client.NewRequestContext(ctx, "GET", "/", "")
+	_, err := client.Do(req, nil)
+	if err != context.DeadlineExceeded {
+		t.Errorf("Do returned error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestDo_response(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Ratelimit-Limit", "5000")
+		w.Header().Set("X-Ratelimit-Remaining", "4999")
+		fmt.Fprint(w, `{"meta":{"code":200},"data":{"id":"1"},"pagination":{"next_max_id":"2"}}`)
+	})
+
+	req, _ := client.NewRequest("GET", "/", "")
+	user := new(User)
+	resp, err := client.Do(req, user)
+	if err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+
+	if want := (&User{ID: "1"}); !reflect.DeepEqual(user, want) {
+		t.Errorf("Do decoded %+v, want %+v", user, want)
+	}
+	if want := (&ResponseMeta{Code: 200}); !reflect.DeepEqual(resp.Meta, want) {
+		t.Errorf("Response.Meta = %+v, want %+v", resp.Meta, want)
+	}
+	if got, want := resp.NextMaxID(), "2"; got != want {
+		t.Errorf("Response.NextMaxID() = %v, want %v", got, want)
+	}
+	rl, err := resp.GetRatelimit()
+	if err != nil {
+		t.Errorf("Response.GetRatelimit returned error: %v", err)
+	}
+	if want := (Ratelimit{Limit: 5000, Remaining: 4999}); rl != want {
+		t.Errorf("Response.GetRatelimit() = %+v, want %+v", rl, want)
+	}
+}
+
+// TestClient_concurrent shares a single Client between many goroutines and
+// checks that every call gets its own pagination back. Run with -race.
+func TestClient_concurrent(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/self/media/recent", func(w http.ResponseWriter, r *http.Request) {
+		id := r.FormValue("max_id")
+		fmt.Fprintf(w, `{"data":[{"id":"%s"}],"pagination":{"next_max_id":"%s"}}`, id, id)
+	})
+	mux.HandleFunc("/tags/search", func(w http.ResponseWriter, r *http.Request) {
+		q := r.FormValue("q")
+		fmt.Fprintf(w, `{"data":[{"name":"%s"}],"pagination":{"next_url":"%s"}}`, q, q)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		id := strconv.Itoa(i)
+		go func() {
+			defer wg.Done()
+			media, page, err := client.Users.RecentMedia("", &Parameters{MaxID: id})
+			if err != nil {
+				t.Errorf("Users.RecentMedia returned error: %v", err)
+				return
+			}
+			if len(media) != 1 || media[0].ID != id {
+				t.Errorf("Users.RecentMedia returned %+v, want ID %v", media, id)
+			}
+			if page.NextMaxID != id {
+				t.Errorf("Users.RecentMedia pagination NextMaxID = %v, want %v", page.NextMaxID, id)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			q := "t" + id
+			tags, page, err := client.Tags.Search(q)
+			if err != nil {
+				t.Errorf("Tags.Search returned error: %v", err)
+				return
+			}
+			if len(tags) != 1 || tags[0].Name != q {
+				t.Errorf("Tags.Search returned %+v, want Name %v", tags, q)
+			}
+			if page.NextURL != q {
+				t.Errorf("Tags.Search pagination NextURL = %v, want %v", page.NextURL, q)
+			}
+		}()
+	}
+	wg.Wait()
+}
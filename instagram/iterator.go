@@ -0,0 +1,148 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"context"
+)
+
+// Iterator walks every page of a list endpoint by following the next_url
+// found in each response's pagination, or its next_cursor for the
+// endpoints paging by cursor. The first page is requested with the
+// parameters given to the method that created the Iterator, so callers don't
+// need to know which max_*_id parameter a particular endpoint expects.
+//
+// Typical use:
+//
+//	it := client.Relationships.FollowedByIterator(ctx, "3", nil)
+//	for it.Next() {
+//		user := it.Value()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle error
+//	}
+type Iterator[T any] struct {
+	// MaxItems stops the iteration after that many items were returned by
+	// Next. Zero means no limit.
+	MaxItems int
+
+	// MaxPages stops the iteration after that many pages were fetched. Zero
+	// means no limit.
+	MaxPages int
+
+	client *Client
+	ctx    context.Context
+	first  func(ctx context.Context) ([]T, *ResponsePagination, error)
+
+	// cursor, if set, fetches the page at a next_cursor. It's used when a
+	// page has no next_url.
+	cursor func(ctx context.Context, cursor string) ([]T, *ResponsePagination, error)
+
+	items []T
+	cur   T
+	page  *ResponsePagination
+	pages int
+	count int
+	done  bool
+	err   error
+}
+
+func newIterator[T any](ctx context.Context, c *Client, first func(ctx context.Context) ([]T, *ResponsePagination, error)) *Iterator[T] {
+	return &Iterator[T]{client: c, ctx: ctx, first: first}
+}
+
+// Next advances the iterator to the next item, fetching the next page when
+// the current one is exhausted. It returns false when there are no more
+// items, a limit has been reached, or an error occurred; use Err to tell
+// these apart.
+func (it *Iterator[T]) Next() bool {
+	if it.MaxItems > 0 && it.count >= it.MaxItems {
+		return false
+	}
+	for len(it.items) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		if it.MaxPages > 0 && it.pages >= it.MaxPages {
+			return false
+		}
+		it.fetch()
+	}
+
+	it.cur = it.items[0]
+	it.items = it.items[1:]
+	it.count++
+	return true
+}
+
+// Value returns the current item. It's only valid after Next returned true.
+func (it *Iterator[T]) Value() T {
+	return it.cur
+}
+
+// Err returns the first error encountered while fetching pages, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Pagination returns the pagination of the most recently fetched page, or
+// nil if no page was fetched yet. It can be used to resume the iteration
+// later on.
+func (it *Iterator[T]) Pagination() *ResponsePagination {
+	return it.page
+}
+
+func (it *Iterator[T]) fetch() {
+	var (
+		items []T
+		page  *ResponsePagination
+		err   error
+	)
+	switch {
+	case it.pages == 0:
+		items, page, err = it.first(it.ctx)
+	case it.page.NextURL != "":
+		items, page, err = it.fetchURL(it.page.NextURL)
+	default:
+		items, page, err = it.cursor(it.ctx, it.page.NextCursor)
+	}
+	if err != nil {
+		it.err = err
+		return
+	}
+	it.pages++
+
+	prev := it.page
+	if page == nil {
+		page = new(ResponsePagination)
+	}
+	it.page = page
+	it.items = items
+
+	// Stop when there's nowhere to go, or when the API hands back the same
+	// next_url or next_cursor again, which would otherwise loop forever.
+	if page.NextURL != "" {
+		it.done = prev != nil && prev.NextURL == page.NextURL
+	} else {
+		it.done = it.cursor == nil || page.NextCursor == "" || (prev != nil && prev.NextCursor == page.NextCursor)
+	}
+}
+
+func (it *Iterator[T]) fetchURL(u string) ([]T, *ResponsePagination, error) {
+	req, err := it.client.NewRequestContext(it.ctx, "GET", u, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := new([]T)
+	resp, err := it.client.Do(req, items)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return *items, resp.Pagination, nil
+}
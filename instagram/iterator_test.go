@@ -0,0 +1,144 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// servePages registers a handler on path that serves pages of media. Each
+// page's next_url points to the next page until the last one.
+func servePages(t *testing.T, path string, pages [][]string) {
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		var n int
+		fmt.Sscan(r.FormValue("page"), &n)
+
+		var data string
+		for i, id := range pages[n] {
+			if i > 0 {
+				data += ","
+			}
+			data += fmt.Sprintf(`{"id":"%s"}`, id)
+		}
+		next := ""
+		if n+1 < len(pages) {
+			next = fmt.Sprintf("%s%s?page=%d", server.URL, path, n+1)
+		}
+		fmt.Fprintf(w, `{"data":[%s],"pagination":{"next_url":"%s"}}`, data, next)
+	})
+}
+
+func collectMedia(it *Iterator[Media]) []string {
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Value().ID)
+	}
+	return ids
+}
+
+func TestIterator_allPages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	servePages(t, "/tags/t/media/recent", [][]string{{"1", "2"}, {"3"}, {"4", "5"}})
+
+	it := client.Tags.RecentMediaIterator(context.Background(), "t", nil)
+	ids := collectMedia(it)
+	if err := it.Err(); err != nil {
+		t.Errorf("Iterator returned error: %v", err)
+	}
+
+	want := []string{"1", "2", "3", "4", "5"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("Iterator returned %v, want %v", ids, want)
+	}
+	if it.Pagination().NextURL != "" {
+		t.Errorf("Iterator Pagination().NextURL = %v, want empty", it.Pagination().NextURL)
+	}
+}
+
+func TestIterator_maxItems(t *testing.T) {
+	setup()
+	defer teardown()
+
+	servePages(t, "/users/self/feed", [][]string{{"1", "2"}, {"3", "4"}, {"5"}})
+
+	it := client.Users.MediaFeedIterator(context.Background(), nil)
+	it.MaxItems = 3
+	ids := collectMedia(it)
+
+	want := []string{"1", "2", "3"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("Iterator returned %v, want %v", ids, want)
+	}
+}
+
+func TestIterator_maxPages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	servePages(t, "/users/self/feed", [][]string{{"1", "2"}, {"3", "4"}, {"5"}})
+
+	it := client.Users.MediaFeedIterator(context.Background(), nil)
+	it.MaxPages = 2
+	ids := collectMedia(it)
+
+	want := []string{"1", "2", "3", "4"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("Iterator returned %v, want %v", ids, want)
+	}
+}
+
+func TestIterator_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/self/followed-by", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("page") == "" {
+			fmt.Fprintf(w, `{"data":[{"id":"1"}],"pagination":{"next_url":"%s/users/self/followed-by?page=1"}}`, server.URL)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"meta":{"code":400,"error_type":"APINotAllowedError","error_message":"you cannot view this resource"}}`)
+	})
+
+	it := client.Relationships.FollowedByIterator(context.Background(), "", nil)
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Value().ID)
+	}
+
+	if want := []string{"1"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Iterator returned %v, want %v", ids, want)
+	}
+	if it.Err() == nil {
+		t.Errorf("Iterator expected error to be returned")
+	}
+	if it.Next() {
+		t.Errorf("Iterator.Next returned true after an error")
+	}
+}
+
+func TestIterator_repeatedNextURL(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/self/feed", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":[{"id":"1"}],"pagination":{"next_url":"%s/users/self/feed?page=1"}}`, server.URL)
+	})
+
+	it := client.Users.MediaFeedIterator(context.Background(), nil)
+	ids := collectMedia(it)
+
+	if want := []string{"1", "1"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Iterator returned %v, want %v", ids, want)
+	}
+}
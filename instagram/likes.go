@@ -6,6 +6,7 @@
 package instagram
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -21,8 +22,13 @@ type LikesService struct {
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/likes/#get_media_likes
 func (s *LikesService) MediaLikes(mediaId string) ([]User, error) {
+	return s.MediaLikesContext(context.Background(), mediaId)
+}
+
+// MediaLikesContext is like MediaLikes but takes a context that controls the request.
+func (s *LikesService) MediaLikesContext(ctx context.Context, mediaId string) ([]User, error) {
 	u := fmt.Sprintf("media/%v/likes", mediaId)
-	req, err := s.client.NewRequest("GET", u, "")
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
 	if err != nil {
 		return nil, err
 	}
@@ -36,19 +42,29 @@ func (s *LikesService) MediaLikes(mediaId string) ([]User, error) {
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/likes/#post_likes
 func (s *LikesService) Like(mediaId string) error {
-	return mediaLikesAction(s, mediaId, "POST")
+	return s.LikeContext(context.Background(), mediaId)
+}
+
+// LikeContext is like Like but takes a context that controls the request.
+func (s *LikesService) LikeContext(ctx context.Context, mediaId string) error {
+	return mediaLikesAction(ctx, s, mediaId, "POST")
 }
 
 // Unlike a media.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/likes/#delete_likes
 func (s *LikesService) Unlike(mediaId string) error {
-	return mediaLikesAction(s, mediaId, "DELETE")
+	return s.UnlikeContext(context.Background(), mediaId)
+}
+
+// UnlikeContext is like Unlike but takes a context that controls the request.
+func (s *LikesService) UnlikeContext(ctx context.Context, mediaId string) error {
+	return mediaLikesAction(ctx, s, mediaId, "DELETE")
 }
 
-func mediaLikesAction(s *LikesService, mediaId, method string) error {
+func mediaLikesAction(ctx context.Context, s *LikesService, mediaId, method string) error {
 	u := fmt.Sprintf("media/%v/likes", mediaId)
-	req, err := s.client.NewRequest(method, u, "")
+	req, err := s.client.NewRequestContext(ctx, method, u, "")
 	if err != nil {
 		return err
 	}
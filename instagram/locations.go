@@ -6,9 +6,9 @@
 package instagram
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"net/url"
-	"strconv"
 )
 
 // LocationsService handles communication with the locations related
@@ -19,20 +19,93 @@ type LocationsService struct {
 	client *Client
 }
 
+// LocationID identifies a location. The API encodes it as a string in
+// location endpoints and as a number in media objects; LocationID decodes
+// from both and encodes as a string.
+type LocationID string
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (id *LocationID) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*id = LocationID(s)
+		return nil
+	}
+	if string(data) == "null" {
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("instagram: invalid location ID %s", data)
+	}
+	*id = LocationID(n.String())
+	return nil
+}
+
 // Location represents information about a location.
 type Location struct {
-	ID        string  `json:"id,omitempty"`
-	Name      string  `json:"name,omitempty"`
-	Latitude  float64 `json:"latitude,omitempty"`
-	Longitude float64 `json:"longitude,omitempty"`
+	ID        LocationID `json:"id,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	Latitude  float64    `json:"latitude,omitempty"`
+	Longitude float64    `json:"longitude,omitempty"`
+}
+
+// LocationRecentMediaOptions specifies the optional parameters to
+// LocationsService.RecentMediaContext.
+type LocationRecentMediaOptions struct {
+	// Return media after MinTimestamp and before MaxTimestamp.
+	MinTimestamp Timestamp `url:"min_timestamp,omitempty"`
+	MaxTimestamp Timestamp `url:"max_timestamp,omitempty"`
+
+	// Return media later than MinID and earlier than MaxID.
+	MinID string `url:"min_id,omitempty"`
+	MaxID string `url:"max_id,omitempty"`
+
+	legacyOptions
+}
+
+// Validate returns an error if any option is out of range.
+func (o *LocationRecentMediaOptions) Validate() error {
+	return validateTimestamps(o.MinTimestamp, o.MaxTimestamp)
+}
+
+// LocationSearchOptions specifies the optional parameters to
+// LocationsService.SearchContext.
+type LocationSearchOptions struct {
+	// Distance from the given coordinate in meters, up to 5000. Defaults to
+	// 1000.
+	Distance float64 `url:"distance,omitempty,precision=7"`
+
+	legacyOptions
+}
+
+// locationSearchQuery holds the parameters of LocationsService.SearchContext.
+type locationSearchQuery struct {
+	Lat float64 `url:"lat,precision=7"`
+	Lng float64 `url:"lng,precision=7"`
+	*LocationSearchOptions
+}
+
+// Validate returns an error if any option is out of range.
+func (o *LocationSearchOptions) Validate() error {
+	return validateDistance(o.Distance)
 }
 
 // Get information about a location.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/locations/#get_locations
 func (s *LocationsService) Get(locationId string) (*Location, error) {
+	return s.GetContext(context.Background(), LocationID(locationId))
+}
+
+// GetContext is like Get but takes a context that controls the request, and
+// the LocationID found in a Location or MediaLocation.
+func (s *LocationsService) GetContext(ctx context.Context, locationId LocationID) (*Location, error) {
 	u := fmt.Sprintf("locations/%v", locationId)
-	req, err := s.client.NewRequest("GET", u, "")
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
 	if err != nil {
 		return nil, err
 	}
@@ -46,62 +119,77 @@ func (s *LocationsService) Get(locationId string) (*Location, error) {
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/locations/#get_locations_media_recent
 func (s *LocationsService) RecentMedia(locationId string, opt *Parameters) ([]Media, *ResponsePagination, error) {
+	return s.RecentMediaContext(context.Background(), LocationID(locationId), opt.locationRecentMediaOptions())
+}
+
+// RecentMediaContext is like RecentMedia but takes a context that controls
+// the request, the LocationID found in a Location or MediaLocation, and
+// options specific to this endpoint.
+func (s *LocationsService) RecentMediaContext(ctx context.Context, locationId LocationID, opt *LocationRecentMediaOptions) ([]Media, *ResponsePagination, error) {
 	u := fmt.Sprintf("locations/%v/media/recent", locationId)
-	if opt != nil {
-		params := url.Values{}
-		if opt.MinTimestamp != 0 {
-			params.Add("min_timestamp", strconv.FormatInt(opt.MinTimestamp, 10))
-		}
-		if opt.MaxTimestamp != 0 {
-			params.Add("max_timestamp", strconv.FormatInt(opt.MaxTimestamp, 10))
-		}
-		if opt.MinID != "" {
-			params.Add("min_id", opt.MinID)
+	if opt != nil && !opt.legacy {
+		if err := opt.Validate(); err != nil {
+			return nil, nil, err
 		}
-		if opt.MaxID != "" {
-			params.Add("max_id", opt.MaxID)
-		}
-		u += "?" + params.Encode()
 	}
-	req, err := s.client.NewRequest("GET", u, "")
+	u, err := addOptions(u, opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
 	if err != nil {
 		return nil, nil, err
 	}
 
 	media := new([]Media)
 
-	_, err = s.client.Do(req, media)
+	resp, err := s.client.Do(req, media)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	page := new(ResponsePagination)
-	if s.client.Response.Pagination != nil {
-		page = s.client.Response.Pagination
+	if resp.Pagination != nil {
+		page = resp.Pagination
 	}
 
 	return *media, page, err
 }
 
+// RecentMediaIterator returns an Iterator over all pages of the media from a given location.
+func (s *LocationsService) RecentMediaIterator(ctx context.Context, locationId LocationID, opt *LocationRecentMediaOptions) *Iterator[Media] {
+	return newIterator(ctx, s.client, func(ctx context.Context) ([]Media, *ResponsePagination, error) {
+		return s.RecentMediaContext(ctx, locationId, opt)
+	})
+}
+
 // Search for a location by geographic coordinate.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/locations/#get_locations_search
 func (s *LocationsService) Search(lat, lng float64, opt *Parameters) ([]Location, error) {
-	u := "locations/search"
-	params := url.Values{}
-	params.Add("lat", strconv.FormatFloat(lat, 'f', 7, 64))
-	params.Add("lng", strconv.FormatFloat(lng, 'f', 7, 64))
-	if opt != nil {
-		if opt.Distance != 0 {
-			distance := opt.Distance
-			if distance > 5000 {
-				distance = 5000
-			}
-			params.Add("distance", strconv.FormatFloat(distance, 'f', 7, 64))
+	return s.SearchContext(context.Background(), lat, lng, opt.locationSearchOptions())
+}
+
+// SearchContext is like Search but takes a context that controls the
+// request, and options specific to this endpoint.
+func (s *LocationsService) SearchContext(ctx context.Context, lat, lng float64, opt *LocationSearchOptions) ([]Location, error) {
+	if opt == nil || !opt.legacy {
+		if err := validateLatLng(lat, lng); err != nil {
+			return nil, err
+		}
+	}
+	if opt != nil && !opt.legacy {
+		if err := opt.Validate(); err != nil {
+			return nil, err
 		}
 	}
-	u += "?" + params.Encode()
-	req, err := s.client.NewRequest("GET", u, "")
+	u, err := addOptions("locations/search", &locationSearchQuery{lat, lng, opt})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
 	if err != nil {
 		return nil, err
 	}
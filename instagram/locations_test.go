@@ -6,6 +6,8 @@
 package instagram
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -92,3 +94,71 @@ func TestLocationsService_RecentMedia(t *testing.T) {
 		t.Errorf("Location.RecentMedia returned %+v, want %+v", media, want)
 	}
 }
+
+func TestLocationsService_Search_clampsDistance(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/locations/search", func(w http.ResponseWriter, r *http.Request) {
+		testFormValues(t, r, values{
+			"distance": strconv.FormatFloat(5000, 'f', 7, 64),
+		})
+		fmt.Fprint(w, `{"data": []}`)
+	})
+
+	if _, err := client.Locations.Search(1, 1, &Parameters{Distance: 10000}); err != nil {
+		t.Errorf("Locations.Search returned error: %v", err)
+	}
+}
+
+func TestLocationID_UnmarshalJSON(t *testing.T) {
+	tests := map[string]LocationID{
+		`"514276"`: "514276",
+		`514276`:   "514276",
+		`null`:     "",
+	}
+	for data, want := range tests {
+		var id LocationID
+		if err := json.Unmarshal([]byte(data), &id); err != nil {
+			t.Errorf("Unmarshal(%s) returned error: %v", data, err)
+		}
+		if id != want {
+			t.Errorf("Unmarshal(%s) = %q, want %q", data, id, want)
+		}
+	}
+
+	var id LocationID
+	if err := json.Unmarshal([]byte(`true`), &id); err == nil {
+		t.Errorf("Unmarshal(true) expected error to be returned")
+	}
+}
+
+func TestMediaLocation_Location(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/media/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"1","location":{"id":514276,"name":"Shibuya","latitude":35.66,"longitude":139.7}}}`)
+	})
+	mux.HandleFunc("/locations/514276", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"514276","name":"Shibuya"}}`)
+	})
+
+	media, err := client.Media.Get("1")
+	if err != nil {
+		t.Fatalf("Media.Get returned error: %v", err)
+	}
+	loc := media.Location.Location()
+	want := &Location{ID: "514276", Name: "Shibuya", Latitude: 35.66, Longitude: 139.7}
+	if !reflect.DeepEqual(loc, want) {
+		t.Errorf("MediaLocation.Location returned %+v, want %+v", loc, want)
+	}
+
+	got, err := client.Locations.GetContext(context.Background(), media.Location.ID)
+	if err != nil {
+		t.Fatalf("Locations.GetContext returned error: %v", err)
+	}
+	if got.ID != loc.ID {
+		t.Errorf("Locations.GetContext returned ID %q, want %q", got.ID, loc.ID)
+	}
+}
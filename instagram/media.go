@@ -6,9 +6,10 @@
 package instagram
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"net/url"
-	"strconv"
 )
 
 // MediaService handles communication with the media related
@@ -19,23 +20,72 @@ type MediaService struct {
 	client *Client
 }
 
-// Media represents a single media (image or video) on Instagram.
+// MediaType is the kind of a media or of an item of a carousel.
+type MediaType string
+
+// Media types.
+const (
+	MediaTypeImage    MediaType = "image"
+	MediaTypeVideo    MediaType = "video"
+	MediaTypeCarousel MediaType = "carousel"
+)
+
+// Media represents a single media (image, video or carousel) on Instagram.
+//
+// The Images, and Videos if any, of a carousel are those of its first item.
+// Use Items to go through all of them.
 type Media struct {
-	Type         string         `json:"type,omitempty"`
-	UsersInPhoto []*UserInPhoto `json:"users_in_photo,omitempty"`
-	Filter       string         `json:"filter,omitempty"`
-	Tags         []string       `json:"tags,omitempty"`
-	Comments     *MediaComments `json:"comments,omitempty"`
-	Caption      *MediaCaption  `json:"caption,omitempty"`
-	Likes        *MediaLikes    `json:"likes,omitempty"`
-	Link         string         `json:"link,omitempty"`
-	User         *User          `json:"user,omitempty"`
-	UserHasLiked bool           `json:"user_has_liked,omitempty"`
-	CreatedTime  int64          `json:"created_time,string,omitempty"`
+	Type          MediaType         `json:"type,omitempty"`
+	UsersInPhoto  []*UserInPhoto    `json:"users_in_photo,omitempty"`
+	Filter        string            `json:"filter,omitempty"`
+	Tags          []string          `json:"tags,omitempty"`
+	Comments      *MediaComments    `json:"comments,omitempty"`
+	Caption       *MediaCaption     `json:"caption,omitempty"`
+	Likes         *MediaLikes       `json:"likes,omitempty"`
+	Link          string            `json:"link,omitempty"`
+	User          *User             `json:"user,omitempty"`
+	UserHasLiked  bool              `json:"user_has_liked,omitempty"`
+	CreatedTime   Timestamp         `json:"created_time,omitzero"`
+	Images        *MediaImages      `json:"images,omitempty"`
+	Videos        *MediaVideos      `json:"videos,omitempty"`
+	ID            string            `json:"id,omitempty"`
+	Location      *MediaLocation    `json:"location,omitempty"`
+	CarouselMedia []*MediaItem      `json:"carousel_media,omitempty"`
+	Attribution   *MediaAttribution `json:"attribution,omitempty"`
+
+	// VideoViews is only reported for videos.
+	VideoViews int `json:"video_views,omitempty"`
+}
+
+// MediaItem is an image or video of a post: one of the children of a
+// carousel, or the post itself.
+type MediaItem struct {
+	Type         MediaType      `json:"type,omitempty"`
 	Images       *MediaImages   `json:"images,omitempty"`
 	Videos       *MediaVideos   `json:"videos,omitempty"`
-	ID           string         `json:"id,omitempty"`
-	Location     *MediaLocation `json:"location,omitempty"`
+	UsersInPhoto []*UserInPhoto `json:"users_in_photo,omitempty"`
+}
+
+// MediaAttribution names the app a media was posted from, when it isn't
+// Instagram itself.
+type MediaAttribution struct {
+	Website   string `json:"website,omitempty"`
+	ItunesURL string `json:"itunes_url,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+// Items returns the items of a carousel, or a single item holding the images
+// and videos of any other media.
+func (m *Media) Items() []*MediaItem {
+	if len(m.CarouselMedia) > 0 {
+		return m.CarouselMedia
+	}
+	return []*MediaItem{{
+		Type:         m.Type,
+		Images:       m.Images,
+		Videos:       m.Videos,
+		UsersInPhoto: m.UsersInPhoto,
+	}}
 }
 
 // MediaComments represents comments on Instagram's media.
@@ -52,10 +102,10 @@ type MediaLikes struct {
 
 // MediaCaption represents caption on Instagram's media.
 type MediaCaption struct {
-	CreatedTime int64  `json:"created_time,string,omitempty"`
-	Text        string `json:"text,omitempty"`
-	From        *User  `json:"from,omitempty"`
-	ID          string `json:"id,omitempty"`
+	CreatedTime Timestamp `json:"created_time,omitzero"`
+	Text        string    `json:"text,omitempty"`
+	From        *User     `json:"from,omitempty"`
+	ID          string    `json:"id,omitempty"`
 }
 
 // UserInPhoto represents a single user, with its position, on Instagram photo.
@@ -65,9 +115,32 @@ type UserInPhoto struct {
 }
 
 // UserInPhotoPosition represents position of the user on Instagram photo.
+// X and Y are relative to the photo's width and height, from 0 to 1, with the
+// origin at the top left corner.
 type UserInPhotoPosition struct {
-	x float64 `json:"x,omitempty"`
-	y float64 `json:"y,omitempty"`
+	X float64 `json:"x,omitempty"`
+	Y float64 `json:"y,omitempty"`
+}
+
+// Pixels returns the position in pixels on img, one of the resolutions of the
+// tagged photo. The result is rounded to the nearest pixel and clamped to the
+// image bounds.
+func (p *UserInPhotoPosition) Pixels(img *MediaImage) (x, y int) {
+	return scale(p.X, img.Width), scale(p.Y, img.Height)
+}
+
+func scale(rel float64, size int) int {
+	if size <= 0 {
+		return 0
+	}
+	px := int(math.Round(rel * float64(size)))
+	if px < 0 {
+		return 0
+	}
+	if px > size-1 {
+		return size - 1
+	}
+	return px
 }
 
 // MediaImages represents MediaImage with various resolutions.
@@ -88,6 +161,7 @@ type MediaImage struct {
 type MediaVideos struct {
 	LowResolution      *MediaVideo `json:"low_resolution,omitempty"`
 	StandardResolution *MediaVideo `json:"standard_resolution,omitempty"`
+	LowBandwidth       *MediaVideo `json:"low_bandwidth,omitempty"`
 }
 
 // MediaVideo represents Instagram media with type video.
@@ -97,24 +171,93 @@ type MediaVideo struct {
 	Height int    `json:"height,omitempty"`
 }
 
-// MediaLocation represents information about a location.
-//
-// There's Location type under LocationsService, the different is
-// the ID type. I've reported this inconsistency to Instagram
-// https://groups.google.com/forum/#!topic/instagram-api-developers/Fty5lOsOGEg
+// MediaLocation represents the location attached to Instagram media.
 type MediaLocation struct {
-	ID        int     `json:"id,omitempty"`
-	Name      string  `json:"name,omitempty"`
-	Latitude  float64 `json:"latitude,omitempty"`
-	Longitude float64 `json:"longitude,omitempty"`
+	ID        LocationID `json:"id,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	Latitude  float64    `json:"latitude,omitempty"`
+	Longitude float64    `json:"longitude,omitempty"`
+}
+
+// Location returns l as a Location. Its ID can be passed straight to
+// LocationsService.GetContext and LocationsService.RecentMediaContext.
+func (l *MediaLocation) Location() *Location {
+	return &Location{
+		ID:        l.ID,
+		Name:      l.Name,
+		Latitude:  l.Latitude,
+		Longitude: l.Longitude,
+	}
+}
+
+// MediaSearchOptions specifies the optional parameters to
+// MediaService.SearchContext.
+type MediaSearchOptions struct {
+	// Return media after MinTimestamp and before MaxTimestamp. The span may
+	// not exceed 7 days.
+	MinTimestamp Timestamp `url:"min_timestamp,omitempty"`
+	MaxTimestamp Timestamp `url:"max_timestamp,omitempty"`
+
+	// Distance from the center in meters, up to 5000. Defaults to 1000.
+	Distance float64 `url:"distance,omitempty,precision=7"`
+
+	legacyOptions
+}
+
+// mediaSearchQuery holds the parameters of MediaService.SearchContext. Nil
+// coordinates aren't sent.
+type mediaSearchQuery struct {
+	Lat *float64 `url:"lat,precision=7"`
+	Lng *float64 `url:"lng,precision=7"`
+	*MediaSearchOptions
+}
+
+// Validate returns an error if any option is out of range.
+func (o *MediaSearchOptions) Validate() error {
+	if err := validateDistance(o.Distance); err != nil {
+		return err
+	}
+	if err := validateTimestamps(o.MinTimestamp, o.MaxTimestamp); err != nil {
+		return err
+	}
+	if !o.MinTimestamp.IsZero() && !o.MaxTimestamp.IsZero() && o.MaxTimestamp.Sub(o.MinTimestamp.Time) > maxSearchSpan {
+		return &OptionError{"MaxTimestamp", o.MaxTimestamp, "must be within 7 days of MinTimestamp"}
+	}
+	return nil
 }
 
 // Get information about a media object.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/media/#get_media
 func (s *MediaService) Get(mediaId string) (*Media, error) {
+	return s.GetContext(context.Background(), mediaId)
+}
+
+// GetContext is like Get but takes a context that controls the request.
+func (s *MediaService) GetContext(ctx context.Context, mediaId string) (*Media, error) {
 	u := fmt.Sprintf("media/%v", mediaId)
-	req, err := s.client.NewRequest("GET", u, "")
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
+	if err != nil {
+		return nil, err
+	}
+
+	media := new(Media)
+	_, err = s.client.Do(req, media)
+	return media, err
+}
+
+// Shortcode gets information about a media object by its shortcode, the
+// last part of its permalink. See ParseShortcode to get it from a link.
+//
+// Instagram API docs: http://instagram.com/developer/endpoints/media/#get_media_by_shortcode
+func (s *MediaService) Shortcode(code string) (*Media, error) {
+	return s.ShortcodeContext(context.Background(), code)
+}
+
+// ShortcodeContext is like Shortcode but takes a context that controls the request.
+func (s *MediaService) ShortcodeContext(ctx context.Context, code string) (*Media, error) {
+	u := fmt.Sprintf("media/shortcode/%v", url.PathEscape(code))
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
 	if err != nil {
 		return nil, err
 	}
@@ -128,42 +271,56 @@ func (s *MediaService) Get(mediaId string) (*Media, error) {
 //
 // http://instagram.com/developer/endpoints/media/#get_media_search
 func (s *MediaService) Search(opt *Parameters) ([]Media, *ResponsePagination, error) {
-	u := "media/search"
+	var lat, lng float64
 	if opt != nil {
-		params := url.Values{}
-		if opt.Lat != 0 {
-			params.Add("lat", strconv.FormatFloat(opt.Lat, 'f', 7, 64))
-		}
-		if opt.Lng != 0 {
-			params.Add("lng", strconv.FormatFloat(opt.Lng, 'f', 7, 64))
+		lat, lng = opt.Lat, opt.Lng
+	}
+	return s.SearchContext(context.Background(), lat, lng, opt.mediaSearchOptions())
+}
+
+// SearchContext is like Search but takes a context that controls the
+// request, the coordinate of the center of the search area, and options
+// specific to this endpoint.
+func (s *MediaService) SearchContext(ctx context.Context, lat, lng float64, opt *MediaSearchOptions) ([]Media, *ResponsePagination, error) {
+	q := &mediaSearchQuery{&lat, &lng, opt}
+	if opt != nil && opt.legacy {
+		// Search sends the coordinates only when they're set.
+		if lat == 0 {
+			q.Lat = nil
 		}
-		if opt.MinTimestamp != 0 {
-			params.Add("min_timestamp", strconv.FormatInt(opt.MinTimestamp, 10))
+		if lng == 0 {
+			q.Lng = nil
 		}
-		if opt.MaxTimestamp != 0 {
-			params.Add("max_timestamp", strconv.FormatInt(opt.MaxTimestamp, 10))
+	} else {
+		if err := validateLatLng(lat, lng); err != nil {
+			return nil, nil, err
 		}
-		if opt.Distance != 0 {
-			params.Add("distance", strconv.FormatFloat(opt.Distance, 'f', 7, 64))
+		if opt != nil {
+			if err := opt.Validate(); err != nil {
+				return nil, nil, err
+			}
 		}
-		u += "?" + params.Encode()
+	}
+	u, err := addOptions("media/search", q)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", u, "")
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
 	if err != nil {
 		return nil, nil, err
 	}
 
 	media := new([]Media)
 
-	_, err = s.client.Do(req, media)
+	resp, err := s.client.Do(req, media)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	page := new(ResponsePagination)
-	if s.client.Response.Pagination != nil {
-		page = s.client.Response.Pagination
+	if resp.Pagination != nil {
+		page = resp.Pagination
 	}
 
 	return *media, page, err
@@ -173,22 +330,27 @@ func (s *MediaService) Search(opt *Parameters) ([]Media, *ResponsePagination, er
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/media/#get_media_popular
 func (s *MediaService) Popular() ([]Media, *ResponsePagination, error) {
+	return s.PopularContext(context.Background())
+}
+
+// PopularContext is like Popular but takes a context that controls the request.
+func (s *MediaService) PopularContext(ctx context.Context) ([]Media, *ResponsePagination, error) {
 	u := "media/popular"
-	req, err := s.client.NewRequest("GET", u, "")
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
 	if err != nil {
 		return nil, nil, err
 	}
 
 	media := new([]Media)
 
-	_, err = s.client.Do(req, media)
+	resp, err := s.client.Do(req, media)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	page := new(ResponsePagination)
-	if s.client.Response.Pagination != nil {
-		page = s.client.Response.Pagination
+	if resp.Pagination != nil {
+		page = resp.Pagination
 	}
 
 	return *media, page, err
@@ -6,6 +6,7 @@
 package instagram
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -32,6 +33,100 @@ func TestMediaService_Get(t *testing.T) {
 	}
 }
 
+func TestMediaService_Get_usersInPhoto(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/media/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"1","users_in_photo":[{"user":{"id":"2"},"position":{"x":0.25,"y":0.5}}]}}`)
+	})
+
+	media, err := client.Media.Get("1")
+	if err != nil {
+		t.Fatalf("Media.Get returned error: %v", err)
+	}
+
+	want := []*UserInPhoto{{User: &User{ID: "2"}, Position: &UserInPhotoPosition{X: 0.25, Y: 0.5}}}
+	if !reflect.DeepEqual(media.UsersInPhoto, want) {
+		t.Errorf("Media.UsersInPhoto = %+v, want %+v", media.UsersInPhoto, want)
+	}
+}
+
+func TestMediaService_Get_carousel(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/media/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"1","type":"carousel",
+			"images":{"thumbnail":{"url":"a.jpg"}},
+			"attribution":{"name":"Layout from Instagram","website":"http://l.instagram.com/"},
+			"carousel_media":[
+				{"type":"image","images":{"thumbnail":{"url":"a.jpg"}},"users_in_photo":[{"position":{"x":0.5,"y":0.5}}]},
+				{"type":"video","images":{"thumbnail":{"url":"b.jpg"}},"videos":{"low_bandwidth":{"url":"b.mp4","width":480,"height":480}}}
+			]}}`)
+	})
+
+	media, err := client.Media.Get("1")
+	if err != nil {
+		t.Fatalf("Media.Get returned error: %v", err)
+	}
+
+	want := []*MediaItem{
+		{
+			Type:         MediaTypeImage,
+			Images:       &MediaImages{Thumbnail: &MediaImage{URL: "a.jpg"}},
+			UsersInPhoto: []*UserInPhoto{{Position: &UserInPhotoPosition{X: 0.5, Y: 0.5}}},
+		},
+		{
+			Type:   MediaTypeVideo,
+			Images: &MediaImages{Thumbnail: &MediaImage{URL: "b.jpg"}},
+			Videos: &MediaVideos{LowBandwidth: &MediaVideo{URL: "b.mp4", Width: 480, Height: 480}},
+		},
+	}
+	if media.Type != MediaTypeCarousel {
+		t.Errorf("Media.Type = %v, want %v", media.Type, MediaTypeCarousel)
+	}
+	if !reflect.DeepEqual(media.Items(), want) {
+		t.Errorf("Media.Items returned %+v, want %+v", media.Items(), want)
+	}
+	if media.Attribution == nil || media.Attribution.Name != "Layout from Instagram" {
+		t.Errorf("Media.Attribution = %+v, want Name Layout from Instagram", media.Attribution)
+	}
+}
+
+func TestMedia_Items(t *testing.T) {
+	m := &Media{
+		Type:       MediaTypeVideo,
+		Images:     &MediaImages{Thumbnail: &MediaImage{URL: "a.jpg"}},
+		Videos:     &MediaVideos{StandardResolution: &MediaVideo{URL: "a.mp4"}},
+		VideoViews: 10,
+	}
+	want := []*MediaItem{{Type: MediaTypeVideo, Images: m.Images, Videos: m.Videos}}
+	if got := m.Items(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Media.Items returned %+v, want %+v", got, want)
+	}
+}
+
+func TestUserInPhotoPosition_Pixels(t *testing.T) {
+	tests := []struct {
+		pos  UserInPhotoPosition
+		img  MediaImage
+		x, y int
+	}{
+		{UserInPhotoPosition{X: 0.25, Y: 0.5}, MediaImage{Width: 640, Height: 640}, 160, 320},
+		{UserInPhotoPosition{X: 0.25, Y: 0.5}, MediaImage{Width: 150, Height: 150}, 38, 75},
+		{UserInPhotoPosition{X: 1, Y: 1}, MediaImage{Width: 306, Height: 306}, 305, 305},
+		{UserInPhotoPosition{X: -0.1, Y: 0}, MediaImage{Width: 306, Height: 306}, 0, 0},
+		{UserInPhotoPosition{X: 0.5, Y: 0.5}, MediaImage{}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if x, y := tt.pos.Pixels(&tt.img); x != tt.x || y != tt.y {
+			t.Errorf("%+v.Pixels(%dx%d) = %d, %d, want %d, %d", tt.pos, tt.img.Width, tt.img.Height, x, y, tt.x, tt.y)
+		}
+	}
+}
+
 func TestMediaService_Search(t *testing.T) {
 	setup()
 	defer teardown()
@@ -66,6 +161,32 @@ func TestMediaService_Search(t *testing.T) {
 	}
 }
 
+func TestMediaService_SearchContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/media/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{
+			"lat":      "0.0000000",
+			"lng":      "-0.1275000",
+			"distance": "500.0000000",
+		})
+		fmt.Fprint(w, `{"data": [{"id":"1"}]}`)
+	})
+
+	opt := &MediaSearchOptions{Distance: 500}
+	media, _, err := client.Media.SearchContext(context.Background(), 0, -0.1275, opt)
+	if err != nil {
+		t.Errorf("Media.SearchContext returned error: %v", err)
+	}
+
+	want := []Media{Media{ID: "1"}}
+	if !reflect.DeepEqual(media, want) {
+		t.Errorf("Media.SearchContext returned %+v, want %+v", media, want)
+	}
+}
+
 func TestMediaService_Popular(t *testing.T) {
 	setup()
 	defer teardown()
@@ -85,3 +206,23 @@ func TestMediaService_Popular(t *testing.T) {
 		t.Errorf("Media.Popular returned %+v, want %+v", media, want)
 	}
 }
+
+func TestMediaService_Shortcode(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/media/shortcode/D", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data":{"id": "3_1"}}`)
+	})
+
+	media, err := client.Media.Shortcode("D")
+	if err != nil {
+		t.Errorf("Media.Shortcode returned error: %v", err)
+	}
+
+	want := &Media{ID: "3_1"}
+	if !reflect.DeepEqual(media, want) {
+		t.Errorf("Media.Shortcode returned %+v, want %+v", media, want)
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package oauth implements Instagram's OAuth 2.0 authorization flow, which
+yields the access token used by instagram.Client.
+
+Redirect the user to the authorization URL, remembering the state:
+
+	conf := &oauth.Config{
+		ClientID:     "8f2c0ad697ea4094beb2b1753b7cde9c",
+		ClientSecret: "...",
+		RedirectURI:  "https://example.com/callback",
+		Scopes:       []string{oauth.ScopeBasic, oauth.ScopeLikes},
+	}
+	state, _ := oauth.NewState()
+	http.Redirect(w, r, conf.AuthCodeURL(state), http.StatusFound)
+
+Then, in the handler for RedirectURI, check the callback and exchange the
+code for a token:
+
+	code, err := oauth.ParseCallback(r, state)
+	if err != nil {
+		// denied by the user, or forged request
+	}
+	token, err := conf.Exchange(r.Context(), code)
+	if err != nil {
+		// ...
+	}
+	client := instagram.NewClient(nil)
+	client.AccessToken = token.AccessToken
+
+Instagram authentication docs: http://instagram.com/developer/authentication/
+*/
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gedex/go-instagram/instagram"
+)
+
+const (
+	// AuthorizeURL is the endpoint users are sent to for authorization.
+	AuthorizeURL = "https://api.instagram.com/oauth/authorize/"
+
+	// TokenURL is the endpoint codes are exchanged for access tokens at.
+	TokenURL = "https://api.instagram.com/oauth/access_token"
+)
+
+// Scopes that can be requested. ScopeBasic is implied when none are given.
+const (
+	ScopeBasic         = "basic"
+	ScopeComments      = "comments"
+	ScopeRelationships = "relationships"
+	ScopeLikes         = "likes"
+)
+
+// Values of the response_type parameter.
+const (
+	// ResponseTypeCode is the server-side (explicit) flow: the user is
+	// redirected back with a code to be passed to Exchange.
+	ResponseTypeCode = "code"
+
+	// ResponseTypeToken is the client-side (implicit) flow: the user is
+	// redirected back with the access token in the URL fragment.
+	ResponseTypeToken = "token"
+)
+
+// ErrStateMismatch is returned when the state sent back by Instagram
+// doesn't match the one the authorization was started with.
+var ErrStateMismatch = errors.New("oauth: state mismatch")
+
+// Config describes an Instagram client application.
+type Config struct {
+	// Application client_id and client_secret.
+	ClientID     string
+	ClientSecret string
+
+	// RedirectURI must match the one registered for the application.
+	RedirectURI string
+
+	// Scopes requested, e.g. ScopeBasic or ScopeLikes.
+	Scopes []string
+
+	// AuthorizeURL and TokenURL override the default endpoints when set.
+	AuthorizeURL string
+	TokenURL     string
+
+	// HTTPClient is used for the token request. http.DefaultClient is used
+	// when it's nil.
+	HTTPClient *http.Client
+}
+
+// Token is the result of a successful code exchange.
+type Token struct {
+	AccessToken string `json:"access_token"`
+
+	// User is the user that authorized the application.
+	User *instagram.User `json:"user"`
+}
+
+// Error is an error returned by the token endpoint, or passed back to
+// RedirectURI when the user denies the authorization.
+type Error struct {
+	Code    int    `json:"code"`
+	Type    string `json:"error_type"`
+	Message string `json:"error_message"`
+}
+
+func (e *Error) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("oauth: %d %v: %v", e.Code, e.Type, e.Message)
+	}
+	return fmt.Sprintf("oauth: %v: %v", e.Type, e.Message)
+}
+
+// AuthCodeURL returns the URL users are sent to in the server-side flow.
+// state is sent back to RedirectURI and must be checked there; see NewState.
+func (c *Config) AuthCodeURL(state string) string {
+	return c.authURL(ResponseTypeCode, state)
+}
+
+// ImplicitURL returns the URL users are sent to in the client-side flow.
+func (c *Config) ImplicitURL(state string) string {
+	return c.authURL(ResponseTypeToken, state)
+}
+
+func (c *Config) authURL(responseType, state string) string {
+	params := url.Values{}
+	params.Add("client_id", c.ClientID)
+	params.Add("redirect_uri", c.RedirectURI)
+	params.Add("response_type", responseType)
+	if len(c.Scopes) > 0 {
+		params.Add("scope", strings.Join(c.Scopes, " "))
+	}
+	if state != "" {
+		params.Add("state", state)
+	}
+
+	u := c.AuthorizeURL
+	if u == "" {
+		u = AuthorizeURL
+	}
+	if strings.Contains(u, "?") {
+		return u + "&" + params.Encode()
+	}
+	return u + "?" + params.Encode()
+}
+
+// Exchange exchanges an authorization code for an access token.
+func (c *Config) Exchange(ctx context.Context, code string) (*Token, error) {
+	params := url.Values{}
+	params.Add("client_id", c.ClientID)
+	params.Add("client_secret", c.ClientSecret)
+	params.Add("grant_type", "authorization_code")
+	params.Add("redirect_uri", c.RedirectURI)
+	params.Add("code", code)
+
+	u := c.TokenURL
+	if u == "" {
+		u = TokenURL
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", u, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("User-Agent", instagram.UserAgent)
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		e := &Error{Code: resp.StatusCode, Type: http.StatusText(resp.StatusCode)}
+		json.Unmarshal(body, e)
+		return nil, e
+	}
+
+	token := new(Token)
+	if err := json.Unmarshal(body, token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("oauth: token endpoint returned no access_token")
+	}
+	return token, nil
+}
+
+// NewState returns a random value suitable for the state parameter.
+func NewState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ValidateState returns ErrStateMismatch unless got equals the non-empty
+// state the authorization was started with.
+func ValidateState(want, got string) error {
+	if want == "" || subtle.ConstantTimeCompare([]byte(want), []byte(got)) != 1 {
+		return ErrStateMismatch
+	}
+	return nil
+}
+
+// ParseCallback checks the request Instagram redirected the user to in the
+// server-side flow and returns the authorization code. It returns an *Error
+// if the user denied the authorization, and ErrStateMismatch if the request
+// doesn't carry the expected state.
+func ParseCallback(r *http.Request, state string) (string, error) {
+	q := r.URL.Query()
+	if q.Get("error") != "" {
+		e := &Error{Type: q.Get("error_reason"), Message: q.Get("error_description")}
+		if e.Type == "" {
+			e.Type = q.Get("error")
+		}
+		return "", e
+	}
+	if err := ValidateState(state, q.Get("state")); err != nil {
+		return "", err
+	}
+
+	code := q.Get("code")
+	if code == "" {
+		return "", errors.New("oauth: callback carries no code")
+	}
+	return code, nil
+}
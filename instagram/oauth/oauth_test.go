@@ -0,0 +1,146 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/gedex/go-instagram/instagram"
+)
+
+var testConfig = Config{
+	ClientID:     "id",
+	ClientSecret: "secret",
+	RedirectURI:  "http://example.com/cb",
+	Scopes:       []string{ScopeBasic, ScopeLikes},
+}
+
+func TestConfig_AuthCodeURL(t *testing.T) {
+	u, err := url.Parse(testConfig.AuthCodeURL("xyz"))
+	if err != nil {
+		t.Fatalf("AuthCodeURL returned an invalid URL: %v", err)
+	}
+
+	if got, want := u.Scheme+"://"+u.Host+u.Path, AuthorizeURL; got != want {
+		t.Errorf("AuthCodeURL endpoint = %v, want %v", got, want)
+	}
+	want := url.Values{
+		"client_id":     {"id"},
+		"redirect_uri":  {"http://example.com/cb"},
+		"response_type": {"code"},
+		"scope":         {"basic likes"},
+		"state":         {"xyz"},
+	}
+	if !reflect.DeepEqual(u.Query(), want) {
+		t.Errorf("AuthCodeURL query = %v, want %v", u.Query(), want)
+	}
+}
+
+func TestConfig_ImplicitURL(t *testing.T) {
+	u, _ := url.Parse(testConfig.ImplicitURL(""))
+	if got := u.Query().Get("response_type"); got != "token" {
+		t.Errorf("ImplicitURL response_type = %v, want token", got)
+	}
+	if _, ok := u.Query()["state"]; ok {
+		t.Errorf("ImplicitURL carries a state although none was given")
+	}
+}
+
+func TestConfig_Exchange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Request method = %v, want POST", r.Method)
+		}
+		want := map[string]string{
+			"client_id":     "id",
+			"client_secret": "secret",
+			"grant_type":    "authorization_code",
+			"redirect_uri":  "http://example.com/cb",
+			"code":          "c0de",
+		}
+		for key, v := range want {
+			if got := r.PostFormValue(key); got != v {
+				t.Errorf("Request parameter %v = %v, want %v", key, got, v)
+			}
+		}
+		fmt.Fprint(w, `{"access_token":"t0ken","user":{"id":"1","username":"u"}}`)
+	}))
+	defer server.Close()
+
+	conf := testConfig
+	conf.TokenURL = server.URL
+	token, err := conf.Exchange(context.Background(), "c0de")
+	if err != nil {
+		t.Fatalf("Exchange returned error: %v", err)
+	}
+
+	want := &Token{AccessToken: "t0ken", User: &instagram.User{ID: "1", Username: "u"}}
+	if !reflect.DeepEqual(token, want) {
+		t.Errorf("Exchange returned %+v, want %+v", token, want)
+	}
+}
+
+func TestConfig_Exchange_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"code":400,"error_type":"OAuthException","error_message":"No matching code found."}`)
+	}))
+	defer server.Close()
+
+	conf := testConfig
+	conf.TokenURL = server.URL
+	_, err := conf.Exchange(context.Background(), "bad")
+
+	want := &Error{Code: 400, Type: "OAuthException", Message: "No matching code found."}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("Exchange returned error %#v, want %#v", err, want)
+	}
+}
+
+func TestValidateState(t *testing.T) {
+	state, err := NewState()
+	if err != nil {
+		t.Fatalf("NewState returned error: %v", err)
+	}
+	if other, _ := NewState(); other == state {
+		t.Errorf("NewState returned %v twice", state)
+	}
+
+	if err := ValidateState(state, state); err != nil {
+		t.Errorf("ValidateState returned error: %v", err)
+	}
+	if err := ValidateState(state, "forged"); err != ErrStateMismatch {
+		t.Errorf("ValidateState returned %v, want %v", err, ErrStateMismatch)
+	}
+	if err := ValidateState("", ""); err != ErrStateMismatch {
+		t.Errorf("ValidateState with empty state returned %v, want %v", err, ErrStateMismatch)
+	}
+}
+
+func TestParseCallback(t *testing.T) {
+	r := httptest.NewRequest("GET", "/cb?code=c0de&state=s", nil)
+	code, err := ParseCallback(r, "s")
+	if err != nil || code != "c0de" {
+		t.Errorf("ParseCallback returned %q, %v, want %q, nil", code, err, "c0de")
+	}
+
+	if _, err := ParseCallback(r, "other"); err != ErrStateMismatch {
+		t.Errorf("ParseCallback returned error %v, want %v", err, ErrStateMismatch)
+	}
+
+	r = httptest.NewRequest("GET", "/cb?error=access_denied&error_reason=user_denied&error_description=The+user+denied+your+request", nil)
+	_, err = ParseCallback(r, "s")
+	want := &Error{Type: "user_denied", Message: "The user denied your request"}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("ParseCallback returned error %#v, want %#v", err, want)
+	}
+}
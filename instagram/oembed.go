@@ -0,0 +1,98 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// OEmbedService handles communication with Instagram's oEmbed endpoint,
+// which returns the HTML to embed a media on a web page. The endpoint lives
+// at the Client's OEmbedURL rather than its BaseURL, and requires no
+// authentication.
+//
+// Instagram API docs: http://instagram.com/developer/embedding/#oembed
+type OEmbedService struct {
+	client *Client
+}
+
+// OEmbed represents the embedding information of a media.
+type OEmbed struct {
+	Version         string `json:"version,omitempty"`
+	Type            string `json:"type,omitempty"`
+	Title           string `json:"title,omitempty"`
+	AuthorName      string `json:"author_name,omitempty"`
+	AuthorURL       string `json:"author_url,omitempty"`
+	AuthorID        int64  `json:"author_id,omitempty"`
+	MediaID         string `json:"media_id,omitempty"`
+	ProviderName    string `json:"provider_name,omitempty"`
+	ProviderURL     string `json:"provider_url,omitempty"`
+	HTML            string `json:"html,omitempty"`
+	Width           int    `json:"width,omitempty"`
+	Height          int    `json:"height,omitempty"`
+	ThumbnailURL    string `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth  int    `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight int    `json:"thumbnail_height,omitempty"`
+}
+
+// OEmbedOptions specifies the optional parameters to OEmbedService.Get.
+type OEmbedOptions struct {
+	// MaxWidth of the embed, between 320 and 658 pixels.
+	MaxWidth int `url:"maxwidth,omitempty"`
+
+	// HideCaption leaves the caption out of the embed.
+	HideCaption bool `url:"hidecaption,omitempty"`
+
+	// OmitScript leaves out the embed.js script tag, for pages that load
+	// it once themselves.
+	OmitScript bool `url:"omitscript,omitempty"`
+}
+
+// oembedQuery holds the parameters of OEmbedService.GetContext.
+type oembedQuery struct {
+	URL string `url:"url"`
+	*OEmbedOptions
+}
+
+// Get the embedding information of the media at link, e.g. Media.Link.
+//
+// Instagram API docs: http://instagram.com/developer/embedding/#oembed
+func (s *OEmbedService) Get(link string, opt *OEmbedOptions) (*OEmbed, error) {
+	return s.GetContext(context.Background(), link, opt)
+}
+
+// GetContext is like Get but takes a context that controls the request.
+func (s *OEmbedService) GetContext(ctx context.Context, link string, opt *OEmbedOptions) (*OEmbed, error) {
+	params, err := encodeQuery(&oembedQuery{link, opt})
+	if err != nil {
+		return nil, err
+	}
+	u := *s.client.OEmbedURL
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("User-Agent", s.client.UserAgent)
+
+	// The response isn't wrapped in the usual envelope, hence no Do.
+	resp, err := s.client.send(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckResponse(resp); err != nil {
+		return nil, err
+	}
+
+	oembed := new(OEmbed)
+	err = json.NewDecoder(resp.Body).Decode(oembed)
+	return oembed, err
+}
@@ -0,0 +1,68 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestOEmbedService_Get(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.AccessToken = "token"
+	client.OEmbedURL, _ = url.Parse(server.URL + "/oembed")
+
+	mux.HandleFunc("/oembed", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{
+			"url":          "http://instagr.am/p/BUG/",
+			"maxwidth":     "320",
+			"hidecaption":  "true",
+			"omitscript":   "true",
+			"access_token": "",
+		})
+		fmt.Fprint(w, `{"version":"1.0","type":"rich","author_name":"diegoquinteiro","media_id":"562_2","html":"<blockquote></blockquote>","width":320,"thumbnail_url":"http://t/1.jpg"}`)
+	})
+
+	opt := &OEmbedOptions{MaxWidth: 320, HideCaption: true, OmitScript: true}
+	oembed, err := client.OEmbed.Get("http://instagr.am/p/BUG/", opt)
+	if err != nil {
+		t.Errorf("OEmbed.Get returned error: %v", err)
+	}
+
+	want := &OEmbed{
+		Version:      "1.0",
+		Type:         "rich",
+		AuthorName:   "diegoquinteiro",
+		MediaID:      "562_2",
+		HTML:         "<blockquote></blockquote>",
+		Width:        320,
+		ThumbnailURL: "http://t/1.jpg",
+	}
+	if !reflect.DeepEqual(oembed, want) {
+		t.Errorf("OEmbed.Get returned %+v, want %+v", oembed, want)
+	}
+}
+
+func TestOEmbedService_Get_notFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.OEmbedURL, _ = url.Parse(server.URL + "/oembed")
+
+	mux.HandleFunc("/oembed", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+
+	if _, err := client.OEmbed.Get("http://instagr.am/p/NOPE/", nil); err == nil {
+		t.Errorf("OEmbed.Get expected error to be returned")
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// maxDistance is the largest search radius, in meters, accepted by the
+	// search endpoints.
+	maxDistance = 5000
+
+	// maxSearchSpan is the longest time span media can be searched over.
+	maxSearchSpan = 7 * 24 * time.Hour
+)
+
+// OptionError is returned when an option passed to a method is out of range.
+// No request is sent in that case.
+type OptionError struct {
+	Option string      // Name of the option's field
+	Value  interface{} // Offending value
+	Reason string
+}
+
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("instagram: invalid option %v = %v: %v", e.Option, e.Value, e.Reason)
+}
+
+func validateCount(count int) error {
+	if count < 0 {
+		return &OptionError{"Count", count, "must not be negative"}
+	}
+	return nil
+}
+
+func validateTimestamps(min, max Timestamp) error {
+	if !min.IsZero() && min.Unix() < 0 {
+		return &OptionError{"MinTimestamp", min, "must not be before 1970"}
+	}
+	if !max.IsZero() && max.Unix() < 0 {
+		return &OptionError{"MaxTimestamp", max, "must not be before 1970"}
+	}
+	if !min.IsZero() && !max.IsZero() && min.After(max.Time) {
+		return &OptionError{"MinTimestamp", min, "must not be after MaxTimestamp"}
+	}
+	return nil
+}
+
+func validateLatLng(lat, lng float64) error {
+	if lat < -90 || lat > 90 {
+		return &OptionError{"Lat", lat, "must be between -90 and 90"}
+	}
+	if lng < -180 || lng > 180 {
+		return &OptionError{"Lng", lng, "must be between -180 and 180"}
+	}
+	return nil
+}
+
+func validateDistance(distance float64) error {
+	if distance < 0 || distance > maxDistance {
+		return &OptionError{"Distance", distance, fmt.Sprintf("must be between 0 and %d meters", maxDistance)}
+	}
+	return nil
+}
+
+// legacyOptions is embedded in the options of each endpoint. It marks the
+// options converted from Parameters by the methods below, which skips their
+// validation: the methods taking Parameters keep sending what they're given,
+// and leave it to the API to reject it.
+type legacyOptions struct {
+	legacy bool
+}
+
+// The methods below convert the legacy Parameters to the options of each
+// endpoint. Fields an endpoint doesn't support are dropped, as they always
+// were.
+
+// count converts a legacy count, which doesn't fit in an int when huge.
+func count(n uint64) int {
+	if n > math.MaxInt {
+		return math.MaxInt
+	}
+	return int(n)
+}
+
+func (p *Parameters) mediaFeedOptions() *MediaFeedOptions {
+	if p == nil {
+		return nil
+	}
+	return &MediaFeedOptions{Count: count(p.Count), MinID: p.MinID, MaxID: p.MaxID, legacyOptions: legacyOptions{true}}
+}
+
+func (p *Parameters) userRecentMediaOptions() *UserRecentMediaOptions {
+	if p == nil {
+		return nil
+	}
+	return &UserRecentMediaOptions{
+		Count:         count(p.Count),
+		MinTimestamp:  Unix(p.MinTimestamp),
+		MaxTimestamp:  Unix(p.MaxTimestamp),
+		MinID:         p.MinID,
+		MaxID:         p.MaxID,
+		legacyOptions: legacyOptions{true},
+	}
+}
+
+func (p *Parameters) likedMediaOptions() *LikedMediaOptions {
+	if p == nil {
+		return nil
+	}
+	return &LikedMediaOptions{Count: count(p.Count), MaxLikeID: p.MaxID, legacyOptions: legacyOptions{true}}
+}
+
+func (p *Parameters) userSearchOptions() *UserSearchOptions {
+	if p == nil {
+		return nil
+	}
+	return &UserSearchOptions{Count: count(p.Count), legacyOptions: legacyOptions{true}}
+}
+
+func (p *Parameters) mediaSearchOptions() *MediaSearchOptions {
+	// The options are never nil, so that the coordinates aren't validated
+	// either.
+	if p == nil {
+		return &MediaSearchOptions{legacyOptions: legacyOptions{true}}
+	}
+	return &MediaSearchOptions{
+		MinTimestamp:  Unix(p.MinTimestamp),
+		MaxTimestamp:  Unix(p.MaxTimestamp),
+		Distance:      p.Distance,
+		legacyOptions: legacyOptions{true},
+	}
+}
+
+func (p *Parameters) tagRecentMediaOptions() *TagRecentMediaOptions {
+	if p == nil {
+		return nil
+	}
+	return &TagRecentMediaOptions{MinID: p.MinID, MaxID: p.MaxID, legacyOptions: legacyOptions{true}}
+}
+
+func (p *Parameters) locationRecentMediaOptions() *LocationRecentMediaOptions {
+	if p == nil {
+		return nil
+	}
+	return &LocationRecentMediaOptions{
+		MinTimestamp:  Unix(p.MinTimestamp),
+		MaxTimestamp:  Unix(p.MaxTimestamp),
+		MinID:         p.MinID,
+		MaxID:         p.MaxID,
+		legacyOptions: legacyOptions{true},
+	}
+}
+
+func (p *Parameters) locationSearchOptions() *LocationSearchOptions {
+	// The options are never nil, so that the coordinates aren't validated
+	// either.
+	if p == nil {
+		return &LocationSearchOptions{legacyOptions: legacyOptions{true}}
+	}
+	// Search used to clamp the distance rather than reject it.
+	distance := p.Distance
+	if distance > maxDistance {
+		distance = maxDistance
+	}
+	return &LocationSearchOptions{Distance: distance, legacyOptions: legacyOptions{true}}
+}
+
+func (p *Parameters) geographyRecentMediaOptions() *GeographyRecentMediaOptions {
+	if p == nil {
+		return nil
+	}
+	return &GeographyRecentMediaOptions{Count: count(p.Count), MinID: p.MinID, legacyOptions: legacyOptions{true}}
+}
+
+func (p *Parameters) relationshipListOptions() *RelationshipListOptions {
+	if p == nil {
+		return nil
+	}
+	return &RelationshipListOptions{Count: count(p.Count), Cursor: p.Cursor, legacyOptions: legacyOptions{true}}
+}
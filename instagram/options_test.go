@@ -0,0 +1,116 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+type validator interface {
+	Validate() error
+}
+
+func TestOptions_Validate(t *testing.T) {
+	tests := []struct {
+		opt    validator
+		option string // field reported in the OptionError, "" if valid
+	}{
+		{&MediaFeedOptions{Count: 10}, ""},
+		{&MediaFeedOptions{Count: -1}, "Count"},
+		{&UserRecentMediaOptions{MinTimestamp: Unix(1), MaxTimestamp: Unix(2)}, ""},
+		{&UserRecentMediaOptions{MinTimestamp: Unix(2), MaxTimestamp: Unix(1)}, "MinTimestamp"},
+		{&UserRecentMediaOptions{MaxTimestamp: Unix(-1)}, "MaxTimestamp"},
+		{&LikedMediaOptions{Count: -5}, "Count"},
+		{&UserSearchOptions{Count: 5}, ""},
+		{&MediaSearchOptions{Distance: 5000}, ""},
+		{&MediaSearchOptions{Distance: 5001}, "Distance"},
+		{&MediaSearchOptions{MinTimestamp: Unix(1), MaxTimestamp: Timestamp{Unix(1).Add(maxSearchSpan)}}, ""},
+		{&MediaSearchOptions{MinTimestamp: Unix(1), MaxTimestamp: Timestamp{Unix(2).Add(maxSearchSpan)}}, "MaxTimestamp"},
+		{&TagRecentMediaOptions{Count: -1}, "Count"},
+		{&LocationRecentMediaOptions{MinTimestamp: Unix(-1)}, "MinTimestamp"},
+		{&LocationSearchOptions{Distance: -1}, "Distance"},
+		{&GeographyRecentMediaOptions{Count: 1}, ""},
+	}
+
+	for _, tt := range tests {
+		err := tt.opt.Validate()
+		if tt.option == "" {
+			if err != nil {
+				t.Errorf("%#v.Validate() returned error: %v", tt.opt, err)
+			}
+			continue
+		}
+		var optErr *OptionError
+		if !errors.As(err, &optErr) || optErr.Option != tt.option {
+			t.Errorf("%#v.Validate() returned %v, want an OptionError for %v", tt.opt, err, tt.option)
+		}
+	}
+}
+
+func TestOptions_invalidNotSent(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Request sent with invalid options: %v", r.URL)
+	})
+
+	ctx := context.Background()
+	if _, _, err := client.Media.SearchContext(ctx, 0, 0, &MediaSearchOptions{Distance: 6000}); err == nil {
+		t.Errorf("Media.SearchContext expected error to be returned")
+	}
+	if _, _, err := client.Media.SearchContext(ctx, 91, 0, nil); err == nil {
+		t.Errorf("Media.SearchContext expected error to be returned")
+	}
+	if _, _, err := client.Media.SearchContext(ctx, 0, -181, nil); err == nil {
+		t.Errorf("Media.SearchContext expected error to be returned")
+	}
+	if _, err := client.Locations.SearchContext(ctx, 100, 0, nil); err == nil {
+		t.Errorf("Locations.SearchContext expected error to be returned")
+	}
+}
+
+func TestOptions_legacyNotValidated(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/media/search", func(w http.ResponseWriter, r *http.Request) {
+		testFormValues(t, r, values{
+			"lat":           "91.0000000",
+			"distance":      "6000.0000000",
+			"min_timestamp": "2",
+			"max_timestamp": "1",
+		})
+		fmt.Fprint(w, `{"data":[]}`)
+	})
+	mux.HandleFunc("/users/self/media/liked", func(w http.ResponseWriter, r *http.Request) {
+		testFormValues(t, r, values{"count": strconv.Itoa(math.MaxInt)})
+		fmt.Fprint(w, `{"data":[]}`)
+	})
+	mux.HandleFunc("/locations/search", func(w http.ResponseWriter, r *http.Request) {
+		testFormValues(t, r, values{"lat": "200.0000000", "lng": "0.0000000"})
+		fmt.Fprint(w, `{"data":[]}`)
+	})
+
+	// The methods taking Parameters send what they always sent, and leave
+	// it to the API to reject it.
+	opt := &Parameters{Lat: 91, Distance: 6000, MinTimestamp: 2, MaxTimestamp: 1}
+	if _, _, err := client.Media.Search(opt); err != nil {
+		t.Errorf("Media.Search returned error: %v", err)
+	}
+	if _, _, err := client.Users.LikedMedia(&Parameters{Count: math.MaxUint64}); err != nil {
+		t.Errorf("Users.LikedMedia returned error: %v", err)
+	}
+	if _, err := client.Locations.Search(200, 0, nil); err != nil {
+		t.Errorf("Locations.Search returned error: %v", err)
+	}
+}
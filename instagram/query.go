@@ -0,0 +1,174 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// addOptions encodes opt with encodeQuery and appends it as the query string
+// of u.
+func addOptions(u string, opt interface{}) (string, error) {
+	params, err := encodeQuery(opt)
+	if err != nil {
+		return u, err
+	}
+	if len(params) == 0 {
+		return u, nil
+	}
+	return u + "?" + params.Encode(), nil
+}
+
+// encodeQuery encodes the fields of the struct opt, or pointer to one, as URL
+// parameters. A nil pointer encodes to no parameters.
+//
+// Each field is named after its "url" tag. Fields without a tag, or tagged
+// "-", are skipped; anonymous struct fields have their own fields encoded as
+// if they belonged to opt. The name may be followed by these options:
+//
+//	omitempty    skip the field if it has its zero value
+//	precision=N  number of decimals of a float, as in strconv.FormatFloat
+//
+// Strings, booleans, integers and floats are encoded with strconv, time.Time
+// and Timestamp as unix seconds, and slices as one parameter per element.
+func encodeQuery(opt interface{}) (url.Values, error) {
+	params := url.Values{}
+	v := reflect.ValueOf(opt)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return params, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("instagram: cannot encode %T as query parameters", opt)
+	}
+	return params, encodeStruct(params, v)
+}
+
+func encodeStruct(params url.Values, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fv := v.Field(i)
+		tag := f.Tag.Get("url")
+
+		if f.Anonymous && tag == "" {
+			for fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					break
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				if err := encodeStruct(params, fv); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if tag == "" || tag == "-" || f.PkgPath != "" {
+			continue
+		}
+
+		name, opts := parseTag(tag)
+		if opts.omitEmpty && isEmpty(fv) {
+			continue
+		}
+		for fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				break
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Ptr {
+			continue
+		}
+
+		if fv.Kind() == reflect.Slice {
+			for j := 0; j < fv.Len(); j++ {
+				s, err := formatValue(fv.Index(j), opts)
+				if err != nil {
+					return fmt.Errorf("instagram: field %v: %v", f.Name, err)
+				}
+				params.Add(name, s)
+			}
+			continue
+		}
+		s, err := formatValue(fv, opts)
+		if err != nil {
+			return fmt.Errorf("instagram: field %v: %v", f.Name, err)
+		}
+		params.Add(name, s)
+	}
+	return nil
+}
+
+type tagOptions struct {
+	omitEmpty bool
+	precision int
+}
+
+func parseTag(tag string) (string, tagOptions) {
+	parts := strings.Split(tag, ",")
+	opts := tagOptions{precision: -1}
+	for _, o := range parts[1:] {
+		switch {
+		case o == "omitempty":
+			opts.omitEmpty = true
+		case strings.HasPrefix(o, "precision="):
+			if p, err := strconv.Atoi(strings.TrimPrefix(o, "precision=")); err == nil {
+				opts.precision = p
+			}
+		}
+	}
+	return parts[0], opts
+}
+
+func formatValue(v reflect.Value, opts tagOptions) (string, error) {
+	if v.Type() == timestampType {
+		v = v.Field(0)
+	}
+	if v.Type() == timeType {
+		return strconv.FormatInt(v.Interface().(time.Time).Unix(), 10), nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', opts.precision, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("unsupported type %v", v.Type())
+}
+
+func isEmpty(v reflect.Value) bool {
+	if v.Type() == timestampType {
+		v = v.Field(0)
+	}
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).IsZero()
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,90 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEncodeQuery(t *testing.T) {
+	type embedded struct {
+		E string `url:"e"`
+	}
+	type opts struct {
+		S       string    `url:"s"`
+		Empty   string    `url:"empty,omitempty"`
+		I       int       `url:"i,omitempty"`
+		F       float64   `url:"f,precision=2"`
+		B       bool      `url:"b,omitempty"`
+		T       time.Time `url:"t,omitempty"`
+		Zero    time.Time `url:"zero,omitempty"`
+		L       []string  `url:"l"`
+		P       *int      `url:"p,omitempty"`
+		NoTag   string
+		Skipped string `url:"-"`
+		*embedded
+	}
+
+	got, err := encodeQuery(&opts{
+		S:        "a b&c",
+		F:        1.5,
+		B:        true,
+		T:        time.Unix(1372176000, 0),
+		L:        []string{"x", "y"},
+		NoTag:    "n",
+		Skipped:  "s",
+		embedded: &embedded{E: "e"},
+	})
+	if err != nil {
+		t.Fatalf("encodeQuery returned error: %v", err)
+	}
+	want := url.Values{
+		"s": {"a b&c"},
+		"f": {"1.50"},
+		"b": {"true"},
+		"t": {"1372176000"},
+		"l": {"x", "y"},
+		"e": {"e"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("encodeQuery returned %v, want %v", got, want)
+	}
+}
+
+func TestEncodeQuery_nil(t *testing.T) {
+	var opt *UserRecentMediaOptions
+	got, err := encodeQuery(opt)
+	if err != nil {
+		t.Fatalf("encodeQuery returned error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("encodeQuery(nil) returned %v, want no parameters", got)
+	}
+}
+
+func TestEncodeQuery_notStruct(t *testing.T) {
+	if _, err := encodeQuery("q"); err == nil {
+		t.Errorf("encodeQuery expected error to be returned")
+	}
+}
+
+func TestAddOptions(t *testing.T) {
+	u, err := addOptions("tags/search", &tagSearchQuery{"go & #fun"})
+	if err != nil {
+		t.Fatalf("addOptions returned error: %v", err)
+	}
+	if want := "tags/search?q=go+%26+%23fun"; u != want {
+		t.Errorf("addOptions returned %q, want %q", u, want)
+	}
+
+	u, _ = addOptions("users/self/feed", (*MediaFeedOptions)(nil))
+	if want := "users/self/feed"; u != want {
+		t.Errorf("addOptions returned %q, want %q", u, want)
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RatelimitWindow is the period over which Instagram counts API calls. The
+// API doesn't tell when the quota resets, so a token that ran out of calls is
+// considered exhausted for RatelimitWindow after the last response that
+// reported it.
+const RatelimitWindow = time.Hour
+
+// RatelimitPolicy controls what Client.Do does when the last response for a
+// token reported that no calls are remaining.
+type RatelimitPolicy int
+
+const (
+	// RatelimitIgnore sends the request anyway and lets the API reject it.
+	RatelimitIgnore RatelimitPolicy = iota
+
+	// RatelimitFailFast returns a *RatelimitError without sending the
+	// request.
+	RatelimitFailFast
+
+	// RatelimitWait blocks until the rate limit window has passed or the
+	// request's context is done.
+	RatelimitWait
+)
+
+// RatelimitError is returned by Client.Do under RatelimitFailFast when the
+// token has no calls remaining.
+type RatelimitError struct {
+	Ratelimit Ratelimit // Last rate limit reported by the API
+	Reset     time.Time // Estimated time the quota becomes available again
+}
+
+func (e *RatelimitError) Error() string {
+	return fmt.Sprintf("instagram: rate limit of %d calls exhausted, estimated reset at %v",
+		e.Ratelimit.Limit, e.Reset.Format(time.RFC3339))
+}
+
+// Is reports whether target is ErrOAuthRateLimit.
+func (e *RatelimitError) Is(target error) bool {
+	return target == ErrOAuthRateLimit
+}
+
+// Ratelimit returns the last rate limit reported by the API for the Client's
+// current AccessToken, or for its ClientID when no token is set. The boolean
+// is false when no response carrying rate limit headers was seen yet.
+func (c *Client) Ratelimit() (Ratelimit, bool) {
+	key := c.AccessToken
+	if key == "" {
+		key = c.ClientID
+	}
+	e, ok := c.ratelimits.get(key)
+	return e.Ratelimit, ok
+}
+
+// A Limiter throttles requests on the client side. Wait blocks until a
+// request may be sent or ctx is done.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// TokenBucket is a Limiter which allows up to burst requests at once and
+// refills at a steady rate. It is safe for concurrent use.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewTokenBucket returns a TokenBucket that allows limit requests every
+// interval, with bursts of up to burst requests. For example, to stay within
+// Instagram's default quota:
+//
+//	client.Limiter = instagram.NewTokenBucket(5000, time.Hour, 10)
+//
+// It panics if limit or interval isn't positive.
+func NewTokenBucket(limit int, interval time.Duration, burst int) *TokenBucket {
+	if limit <= 0 || interval <= 0 {
+		panic("instagram: non-positive limit or interval for NewTokenBucket")
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	b := &TokenBucket{
+		rate:   float64(limit) / interval.Seconds(),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}
+	b.last = b.now()
+	return b
+}
+
+// Wait takes a token from the bucket, blocking until one is available or ctx
+// is done.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := b.now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	// Take the token right away, possibly going negative, so concurrent
+	// callers queue up behind each other.
+	b.tokens--
+	var wait time.Duration
+	if b.tokens < 0 {
+		wait = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+type ratelimitEntry struct {
+	Ratelimit
+	seen time.Time
+}
+
+// ratelimitTracker remembers the latest rate limit reported per token.
+type ratelimitTracker struct {
+	mu      sync.Mutex
+	entries map[string]ratelimitEntry
+}
+
+func (t *ratelimitTracker) get(key string) (ratelimitEntry, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.entries[key]
+	return e, ok
+}
+
+func (t *ratelimitTracker) set(key string, rl Ratelimit, seen time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.entries == nil {
+		t.entries = make(map[string]ratelimitEntry)
+	}
+	t.entries[key] = ratelimitEntry{Ratelimit: rl, seen: seen}
+}
+
+// ratelimitKey returns the credential a request is accounted against.
+func ratelimitKey(req *http.Request) string {
+	q := req.URL.Query()
+	if token := q.Get("access_token"); token != "" {
+		return token
+	}
+	return q.Get("client_id")
+}
+
+// checkRatelimit waits on the Client's Limiter and applies its
+// RatelimitPolicy before req is sent.
+func (c *Client) checkRatelimit(req *http.Request) error {
+	ctx := req.Context()
+	if c.Limiter != nil {
+		if err := c.Limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if c.RatelimitPolicy == RatelimitIgnore {
+		return nil
+	}
+
+	e, ok := c.ratelimits.get(ratelimitKey(req))
+	if !ok || e.Remaining > 0 {
+		return nil
+	}
+	reset := e.seen.Add(RatelimitWindow)
+	wait := time.Until(reset)
+	if wait <= 0 {
+		return nil
+	}
+
+	if c.RatelimitPolicy == RatelimitFailFast {
+		return &RatelimitError{Ratelimit: e.Ratelimit, Reset: reset}
+	}
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// trackRatelimit records the rate limit headers of resp, if present.
+func (c *Client) trackRatelimit(resp *http.Response) {
+	r := &Response{Response: resp}
+	rl, err := r.GetRatelimit()
+	if err != nil {
+		return
+	}
+	c.ratelimits.set(ratelimitKey(resp.Request), rl, time.Now())
+}
@@ -0,0 +1,170 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func serveRatelimit(remaining int) {
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Ratelimit-Limit", "5000")
+		w.Header().Set("X-Ratelimit-Remaining", fmt.Sprint(remaining))
+		fmt.Fprint(w, `{"data":{}}`)
+	})
+}
+
+func TestClient_Ratelimit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveRatelimit(42)
+
+	client.AccessToken = "a"
+	if _, ok := client.Ratelimit(); ok {
+		t.Errorf("Client.Ratelimit reported a rate limit before any request")
+	}
+
+	req, _ := client.NewRequest("GET", "/", "")
+	if _, err := client.Do(req, nil); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+
+	rl, ok := client.Ratelimit()
+	if want := (Ratelimit{Limit: 5000, Remaining: 42}); !ok || rl != want {
+		t.Errorf("Client.Ratelimit() = %+v, %v, want %+v, true", rl, ok, want)
+	}
+
+	// Rate limits are tracked per token.
+	client.AccessToken = "b"
+	if _, ok := client.Ratelimit(); ok {
+		t.Errorf("Client.Ratelimit reported a rate limit for an unused token")
+	}
+}
+
+func TestClient_RatelimitFailFast(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveRatelimit(0)
+
+	client.AccessToken = "a"
+	client.RatelimitPolicy = RatelimitFailFast
+
+	req, _ := client.NewRequest("GET", "/", "")
+	if _, err := client.Do(req, nil); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+
+	req, _ = client.NewRequest("GET", "/", "")
+	_, err := client.Do(req, nil)
+	rlErr, ok := err.(*RatelimitError)
+	if !ok {
+		t.Fatalf("Do returned error %v, want *RatelimitError", err)
+	}
+	if rlErr.Ratelimit.Limit != 5000 {
+		t.Errorf("RatelimitError.Ratelimit.Limit = %v, want 5000", rlErr.Ratelimit.Limit)
+	}
+	if d := time.Until(rlErr.Reset); d <= 0 || d > RatelimitWindow {
+		t.Errorf("RatelimitError.Reset = %v, want within the next %v", rlErr.Reset, RatelimitWindow)
+	}
+}
+
+func TestClient_RatelimitWait(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveRatelimit(0)
+
+	client.AccessToken = "a"
+	client.RatelimitPolicy = RatelimitWait
+
+	req, _ := client.NewRequest("GET", "/", "")
+	if _, err := client.Do(req, nil); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ = client.NewRequestContext(ctx, "GET", "/", "")
+	if _, err := client.Do(req, nil); err != context.DeadlineExceeded {
+		t.Errorf("Do returned error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewTokenBucket(1, time.Second, 2)
+	b.now = func() time.Time { return now }
+	b.last = now
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := b.Wait(ctx); err != nil {
+			t.Fatalf("TokenBucket.Wait returned error: %v", err)
+		}
+	}
+
+	// The bucket is empty: the next call must block until ctx is done.
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := b.Wait(ctx); err != context.Canceled {
+		t.Errorf("TokenBucket.Wait returned error %v, want %v", err, context.Canceled)
+	}
+
+	// One second later a token is available again.
+	now = now.Add(time.Second)
+	if err := b.Wait(context.Background()); err != nil {
+		t.Errorf("TokenBucket.Wait returned error: %v", err)
+	}
+}
+
+func TestNewTokenBucket_invalid(t *testing.T) {
+	tests := []struct {
+		limit    int
+		interval time.Duration
+	}{
+		{0, time.Second},
+		{-1, time.Second},
+		{1, 0},
+		{1, -time.Second},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewTokenBucket(%d, %v, 1) didn't panic", tt.limit, tt.interval)
+				}
+			}()
+			NewTokenBucket(tt.limit, tt.interval, 1)
+		}()
+	}
+}
+
+func TestClient_Limiter(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveRatelimit(100)
+
+	client.Limiter = NewTokenBucket(1, time.Hour, 1)
+
+	req, _ := client.NewRequest("GET", "/", "")
+	if _, err := client.Do(req, nil); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ = client.NewRequestContext(ctx, "GET", "/", "")
+	if _, err := client.Do(req, nil); err != context.DeadlineExceeded {
+		t.Errorf("Do returned error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+)
+
+// maxUpdateSize caps the size of an update batch read by RealtimeHandler.
+const maxUpdateSize = 1 << 20
+
+// Update represents a single notification sent by Instagram to the callback
+// URL of a real-time subscription.
+//
+// Instagram API docs: http://instagram.com/developer/realtime/#receiving-updates
+type Update struct {
+	ChangedAspect  string      `json:"changed_aspect,omitempty"`
+	Object         string      `json:"object,omitempty"`
+	ObjectID       string      `json:"object_id,omitempty"`
+	Time           int64       `json:"time,omitempty"`
+	SubscriptionID int64       `json:"subscription_id,omitempty"`
+	Data           *UpdateData `json:"data,omitempty"`
+}
+
+// UpdateData carries the object-specific part of an Update.
+type UpdateData struct {
+	MediaID string `json:"media_id,omitempty"`
+}
+
+// RealtimeHandler is an http.Handler to be mounted at the callback URL of
+// real-time subscriptions. It answers the verification handshake Instagram
+// performs when a subscription is created, checks the signature of every
+// batch of updates and dispatches the updates to Handle and Updates.
+//
+//	h := &instagram.RealtimeHandler{
+//		ClientSecret: client.ClientSecret,
+//		VerifyToken:  "s3cret",
+//		Handle: func(updates []instagram.Update) {
+//			// ...
+//		},
+//	}
+//	http.Handle("/instagram/callback", h)
+//
+// Instagram expects the callback to answer within a few seconds, so slow
+// work should be done asynchronously.
+type RealtimeHandler struct {
+	// ClientSecret is used to verify the X-Hub-Signature header of updates.
+	// Updates are rejected when it's empty.
+	ClientSecret string
+
+	// VerifyToken must match the one passed to SubscriptionsService.Create.
+	// The handshake is accepted regardless of the token when it's empty.
+	VerifyToken string
+
+	// Handle, if set, is called with every batch of updates.
+	Handle func(updates []Update)
+
+	// Updates, if set, receives every update. Sends block, so the channel
+	// should be buffered or drained promptly; the updates not sent yet when
+	// Instagram gives up on the request are dropped.
+	Updates chan<- Update
+}
+
+func (h *RealtimeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		h.serveChallenge(w, r)
+	case "POST":
+		h.serveUpdates(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *RealtimeHandler) serveChallenge(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("hub.mode") != "subscribe" || q.Get("hub.challenge") == "" {
+		http.Error(w, "missing hub.challenge", http.StatusBadRequest)
+		return
+	}
+	if h.VerifyToken != "" && !hmac.Equal([]byte(q.Get("hub.verify_token")), []byte(h.VerifyToken)) {
+		http.Error(w, "invalid hub.verify_token", http.StatusForbidden)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write([]byte(q.Get("hub.challenge")))
+}
+
+func (h *RealtimeHandler) serveUpdates(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxUpdateSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.ClientSecret == "" || !VerifySignature(h.ClientSecret, body, r.Header.Get("X-Hub-Signature")) {
+		http.Error(w, "invalid X-Hub-Signature", http.StatusForbidden)
+		return
+	}
+
+	var updates []Update
+	if err := json.Unmarshal(body, &updates); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if h.Handle != nil {
+		h.Handle(updates)
+	}
+	if h.Updates != nil {
+		for _, u := range updates {
+			select {
+			case h.Updates <- u:
+			case <-r.Context().Done():
+				http.Error(w, "updates not consumed in time", http.StatusServiceUnavailable)
+				return
+			}
+		}
+	}
+}
+
+// VerifySignature reports whether signature, the hex encoded value of the
+// X-Hub-Signature header, is the HMAC-SHA1 of body keyed with clientSecret.
+func VerifySignature(clientSecret string, body []byte, signature string) bool {
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha1.New, []byte(clientSecret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
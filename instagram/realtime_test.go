@@ -0,0 +1,126 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testUpdates = `[
+	{"changed_aspect":"media","object":"user","object_id":"1234","time":1297286541,"subscription_id":1,"data":{"media_id":"1_1234"}},
+	{"changed_aspect":"media","object":"tag","object_id":"nofilter","time":1297286542,"subscription_id":2}
+]`
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestRealtimeHandler_challenge(t *testing.T) {
+	h := &RealtimeHandler{VerifyToken: "v"}
+
+	r := httptest.NewRequest("GET", "/cb?hub.mode=subscribe&hub.challenge=15f7d1a91c1f40f8&hub.verify_token=v", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || w.Body.String() != "15f7d1a91c1f40f8" {
+		t.Errorf("Challenge response = %d %q, want 200 %q", w.Code, w.Body.String(), "15f7d1a91c1f40f8")
+	}
+
+	r = httptest.NewRequest("GET", "/cb?hub.mode=subscribe&hub.challenge=15f7d1a91c1f40f8&hub.verify_token=x", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Challenge with wrong verify token response code = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestRealtimeHandler_updates(t *testing.T) {
+	var got []Update
+	ch := make(chan Update, 2)
+	h := &RealtimeHandler{
+		ClientSecret: "secret",
+		Handle:       func(updates []Update) { got = updates },
+		Updates:      ch,
+	}
+
+	r := httptest.NewRequest("POST", "/cb", strings.NewReader(testUpdates))
+	r.Header.Set("X-Hub-Signature", sign("secret", testUpdates))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Updates response code = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	want := []Update{
+		{ChangedAspect: "media", Object: "user", ObjectID: "1234", Time: 1297286541, SubscriptionID: 1, Data: &UpdateData{MediaID: "1_1234"}},
+		{ChangedAspect: "media", Object: "tag", ObjectID: "nofilter", Time: 1297286542, SubscriptionID: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Handle received %+v, want %+v", got, want)
+	}
+	close(ch)
+	var fromChan []Update
+	for u := range ch {
+		fromChan = append(fromChan, u)
+	}
+	if !reflect.DeepEqual(fromChan, want) {
+		t.Errorf("Updates received %+v, want %+v", fromChan, want)
+	}
+}
+
+func TestRealtimeHandler_badSignature(t *testing.T) {
+	h := &RealtimeHandler{
+		ClientSecret: "secret",
+		Handle:       func([]Update) { t.Errorf("Handle called for an unsigned batch") },
+	}
+
+	for _, sig := range []string{"", "zz", sign("other", testUpdates)} {
+		r := httptest.NewRequest("POST", "/cb", strings.NewReader(testUpdates))
+		r.Header.Set("X-Hub-Signature", sig)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Updates with signature %q response code = %d, want %d", sig, w.Code, http.StatusForbidden)
+		}
+	}
+}
+
+func TestRealtimeHandler_updatesCanceled(t *testing.T) {
+	h := &RealtimeHandler{
+		ClientSecret: "secret",
+		Updates:      make(chan Update), // never drained
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := httptest.NewRequest("POST", "/cb", strings.NewReader(testUpdates)).WithContext(ctx)
+	r.Header.Set("X-Hub-Signature", sign("secret", testUpdates))
+	w := httptest.NewRecorder()
+
+	done := make(chan bool)
+	go func() {
+		h.ServeHTTP(w, r)
+		close(done)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("ServeHTTP still blocked after the request was canceled")
+	}
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Response code = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
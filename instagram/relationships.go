@@ -6,6 +6,7 @@
 package instagram
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -27,150 +28,241 @@ type Relationship struct {
 	IncomingStatus string `json:"incoming_status,omitempty"`
 }
 
+// RelationshipListOptions specifies the optional parameters of the
+// RelationshipsService methods listing users.
+type RelationshipListOptions struct {
+	// Count of users to return.
+	Count int `url:"count,omitempty"`
+
+	// Cursor returns the page following the one whose pagination had this
+	// NextCursor.
+	Cursor string `url:"cursor,omitempty"`
+
+	legacyOptions
+}
+
+// Validate returns an error if any option is out of range.
+func (o *RelationshipListOptions) Validate() error {
+	return validateCount(o.Count)
+}
+
+// withCursor returns a copy of o with Cursor set to cursor.
+func (o *RelationshipListOptions) withCursor(cursor string) *RelationshipListOptions {
+	c := RelationshipListOptions{Cursor: cursor}
+	if o != nil {
+		c.Count = o.Count
+	}
+	return &c
+}
+
 // Follows gets the list of users this user follows. If empty string is
 // passed then it refers to `self` or curret authenticated user.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/relationships/#get_users_follows
-func (s *RelationshipsService) Follows(userId string) ([]User, *ResponsePagination, error) {
+func (s *RelationshipsService) Follows(userId string, opt *Parameters) ([]User, *ResponsePagination, error) {
+	return s.FollowsContext(context.Background(), userId, opt.relationshipListOptions())
+}
+
+// FollowsContext is like Follows but takes a context that controls the
+// request, and options specific to this endpoint.
+func (s *RelationshipsService) FollowsContext(ctx context.Context, userId string, opt *RelationshipListOptions) ([]User, *ResponsePagination, error) {
 	var u string
 	if userId != "" {
 		u = fmt.Sprintf("users/%v/follows", userId)
 	} else {
 		u = "users/self/follows"
 	}
+	return relationshipList(ctx, s, u, opt)
+}
 
-	req, err := s.client.NewRequest("GET", u, "")
-	if err != nil {
-		return nil, nil, err
-	}
-
-	users := new([]User)
-
-	_, err = s.client.Do(req, users)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	page := new(ResponsePagination)
-	if s.client.Response.Pagination != nil {
-		page = s.client.Response.Pagination
+// FollowsIterator returns an Iterator over all pages of the users this user follows.
+func (s *RelationshipsService) FollowsIterator(ctx context.Context, userId string, opt *RelationshipListOptions) *Iterator[User] {
+	it := newIterator(ctx, s.client, func(ctx context.Context) ([]User, *ResponsePagination, error) {
+		return s.FollowsContext(ctx, userId, opt)
+	})
+	it.cursor = func(ctx context.Context, cursor string) ([]User, *ResponsePagination, error) {
+		return s.FollowsContext(ctx, userId, opt.withCursor(cursor))
 	}
-
-	return *users, page, err
+	return it
 }
 
 // FollowedBy gets the list of users this user is followed by. If empty string is
 // passed then it refers to `self` or curret authenticated user.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/relationships/#get_users_followed_by
-func (s *RelationshipsService) FollowedBy(userId string) ([]User, *ResponsePagination, error) {
+func (s *RelationshipsService) FollowedBy(userId string, opt *Parameters) ([]User, *ResponsePagination, error) {
+	return s.FollowedByContext(context.Background(), userId, opt.relationshipListOptions())
+}
+
+// FollowedByContext is like FollowedBy but takes a context that controls the
+// request, and options specific to this endpoint.
+func (s *RelationshipsService) FollowedByContext(ctx context.Context, userId string, opt *RelationshipListOptions) ([]User, *ResponsePagination, error) {
 	var u string
 	if userId != "" {
 		u = fmt.Sprintf("users/%v/followed-by", userId)
 	} else {
 		u = "users/self/followed-by"
 	}
+	return relationshipList(ctx, s, u, opt)
+}
 
-	req, err := s.client.NewRequest("GET", u, "")
-	if err != nil {
-		return nil, nil, err
+// FollowedByIterator returns an Iterator over all pages of the users this user is followed by.
+func (s *RelationshipsService) FollowedByIterator(ctx context.Context, userId string, opt *RelationshipListOptions) *Iterator[User] {
+	it := newIterator(ctx, s.client, func(ctx context.Context) ([]User, *ResponsePagination, error) {
+		return s.FollowedByContext(ctx, userId, opt)
+	})
+	it.cursor = func(ctx context.Context, cursor string) ([]User, *ResponsePagination, error) {
+		return s.FollowedByContext(ctx, userId, opt.withCursor(cursor))
 	}
+	return it
+}
 
-	users := new([]User)
+// RequestedBy lists the users who have requested this user's permission to follow.
+//
+// Instagram API docs: http://instagram.com/developer/endpoints/relationships/#get_incoming_requests
+func (s *RelationshipsService) RequestedBy(opt *Parameters) ([]User, *ResponsePagination, error) {
+	return s.RequestedByContext(context.Background(), opt.relationshipListOptions())
+}
 
-	_, err = s.client.Do(req, users)
-	if err != nil {
-		return nil, nil, err
-	}
+// RequestedByContext is like RequestedBy but takes a context that controls
+// the request, and options specific to this endpoint.
+func (s *RelationshipsService) RequestedByContext(ctx context.Context, opt *RelationshipListOptions) ([]User, *ResponsePagination, error) {
+	return relationshipList(ctx, s, "users/self/requested-by", opt)
+}
 
-	page := new(ResponsePagination)
-	if s.client.Response.Pagination != nil {
-		page = s.client.Response.Pagination
+// RequestedByIterator returns an Iterator over all pages of the users who have requested permission to follow.
+func (s *RelationshipsService) RequestedByIterator(ctx context.Context, opt *RelationshipListOptions) *Iterator[User] {
+	it := newIterator(ctx, s.client, func(ctx context.Context) ([]User, *ResponsePagination, error) {
+		return s.RequestedByContext(ctx, opt)
+	})
+	it.cursor = func(ctx context.Context, cursor string) ([]User, *ResponsePagination, error) {
+		return s.RequestedByContext(ctx, opt.withCursor(cursor))
 	}
-
-	return *users, page, err
+	return it
 }
 
-// RequestedBy lists the users who have requested this user's permission to follow.
-//
-// Instagram API docs: http://instagram.com/developer/endpoints/relationships/#get_incoming_requests
-func (s *RelationshipsService) RequestedBy() ([]User, *ResponsePagination, error) {
-	u := "users/self/requested-by"
-	req, err := s.client.NewRequest("GET", u, "")
+// relationshipList gets the page of users at u.
+func relationshipList(ctx context.Context, s *RelationshipsService, u string, opt *RelationshipListOptions) ([]User, *ResponsePagination, error) {
+	if opt != nil && !opt.legacy {
+		if err := opt.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+	u, err := addOptions(u, opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
 	if err != nil {
 		return nil, nil, err
 	}
 
 	users := new([]User)
 
-	_, err = s.client.Do(req, users)
+	resp, err := s.client.Do(req, users)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	page := new(ResponsePagination)
-	if s.client.Response.Pagination != nil {
-		page = s.client.Response.Pagination
+	if resp.Pagination != nil {
+		page = resp.Pagination
 	}
 
-	return *users, page, err
+	return *users, page, nil
 }
 
 // Relationship gets information about a relationship to another user.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/relationships/#get_relationship
 func (s *RelationshipsService) Relationship(userId string) (*Relationship, error) {
-	return relationshipAction(s, userId, "", "GET")
+	return s.RelationshipContext(context.Background(), userId)
+}
+
+// RelationshipContext is like Relationship but takes a context that controls the request.
+func (s *RelationshipsService) RelationshipContext(ctx context.Context, userId string) (*Relationship, error) {
+	return relationshipAction(ctx, s, userId, "", "GET")
 }
 
 // Follow a user.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/relationships/#post_relationship
 func (s *RelationshipsService) Follow(userId string) (*Relationship, error) {
-	return relationshipAction(s, userId, "follow", "POST")
+	return s.FollowContext(context.Background(), userId)
+}
+
+// FollowContext is like Follow but takes a context that controls the request.
+func (s *RelationshipsService) FollowContext(ctx context.Context, userId string) (*Relationship, error) {
+	return relationshipAction(ctx, s, userId, "follow", "POST")
 }
 
 // Unfollow a user.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/relationships/#post_relationship
 func (s *RelationshipsService) Unfollow(userId string) (*Relationship, error) {
-	return relationshipAction(s, userId, "unfollow", "POST")
+	return s.UnfollowContext(context.Background(), userId)
+}
+
+// UnfollowContext is like Unfollow but takes a context that controls the request.
+func (s *RelationshipsService) UnfollowContext(ctx context.Context, userId string) (*Relationship, error) {
+	return relationshipAction(ctx, s, userId, "unfollow", "POST")
 }
 
 // Block a user.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/relationships/#post_relationship
 func (s *RelationshipsService) Block(userId string) (*Relationship, error) {
-	return relationshipAction(s, userId, "block", "POST")
+	return s.BlockContext(context.Background(), userId)
+}
+
+// BlockContext is like Block but takes a context that controls the request.
+func (s *RelationshipsService) BlockContext(ctx context.Context, userId string) (*Relationship, error) {
+	return relationshipAction(ctx, s, userId, "block", "POST")
 }
 
 // Unblock a user.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/relationships/#post_relationship
 func (s *RelationshipsService) Unblock(userId string) (*Relationship, error) {
-	return relationshipAction(s, userId, "unblock", "POST")
+	return s.UnblockContext(context.Background(), userId)
+}
+
+// UnblockContext is like Unblock but takes a context that controls the request.
+func (s *RelationshipsService) UnblockContext(ctx context.Context, userId string) (*Relationship, error) {
+	return relationshipAction(ctx, s, userId, "unblock", "POST")
 }
 
 // Approve a user.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/relationships/#post_relationship
 func (s *RelationshipsService) Approve(userId string) (*Relationship, error) {
-	return relationshipAction(s, userId, "approve", "POST")
+	return s.ApproveContext(context.Background(), userId)
+}
+
+// ApproveContext is like Approve but takes a context that controls the request.
+func (s *RelationshipsService) ApproveContext(ctx context.Context, userId string) (*Relationship, error) {
+	return relationshipAction(ctx, s, userId, "approve", "POST")
 }
 
 // Deny a user.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/relationships/#post_relationship
 func (s *RelationshipsService) Deny(userId string) (*Relationship, error) {
-	return relationshipAction(s, userId, "deny", "POST")
+	return s.DenyContext(context.Background(), userId)
+}
+
+// DenyContext is like Deny but takes a context that controls the request.
+func (s *RelationshipsService) DenyContext(ctx context.Context, userId string) (*Relationship, error) {
+	return relationshipAction(ctx, s, userId, "deny", "POST")
 }
 
-func relationshipAction(s *RelationshipsService, userId, action, method string) (*Relationship, error) {
+func relationshipAction(ctx context.Context, s *RelationshipsService, userId, action, method string) (*Relationship, error) {
 	u := fmt.Sprintf("users/%v/relationship", userId)
 	if action != "" {
 		action = "action=" + action
 	}
-	req, err := s.client.NewRequest(method, u, action)
+	req, err := s.client.NewRequestContext(ctx, method, u, action)
 	if err != nil {
 		return nil, err
 	}
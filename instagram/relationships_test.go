@@ -6,6 +6,7 @@
 package instagram
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -21,7 +22,7 @@ func TestRelationshipsService_Follows_self(t *testing.T) {
 		fmt.Fprint(w, `{"data": [{"id":"1"}]}`)
 	})
 
-	users, _, err := client.Relationships.Follows("")
+	users, _, err := client.Relationships.Follows("", nil)
 	if err != nil {
 		t.Errorf("Relationships.Follows returned error: %v", err)
 	}
@@ -41,7 +42,7 @@ func TestRelationshipsService_Follows_userId(t *testing.T) {
 		fmt.Fprint(w, `{"data": [{"id":"1"}]}`)
 	})
 
-	users, _, err := client.Relationships.Follows("1")
+	users, _, err := client.Relationships.Follows("1", nil)
 	if err != nil {
 		t.Errorf("Relationships.Follows returned error: %v", err)
 	}
@@ -61,7 +62,7 @@ func TestRelationshipsService_FollowedBy_self(t *testing.T) {
 		fmt.Fprint(w, `{"data": [{"id":"1"}]}`)
 	})
 
-	users, _, err := client.Relationships.FollowedBy("")
+	users, _, err := client.Relationships.FollowedBy("", nil)
 	if err != nil {
 		t.Errorf("Relationships.FollowedBy returned error: %v", err)
 	}
@@ -81,7 +82,7 @@ func TestRelationshipsService_FollowedBy_userId(t *testing.T) {
 		fmt.Fprint(w, `{"data": [{"id":"1"}]}`)
 	})
 
-	users, _, err := client.Relationships.FollowedBy("1")
+	users, _, err := client.Relationships.FollowedBy("1", nil)
 	if err != nil {
 		t.Errorf("Relationships.FollowedBy returned error: %v", err)
 	}
@@ -101,7 +102,7 @@ func TestRelationshipsService_RequestedBy(t *testing.T) {
 		fmt.Fprint(w, `{"data": [{"id":"1"}]}`)
 	})
 
-	users, _, err := client.Relationships.RequestedBy()
+	users, _, err := client.Relationships.RequestedBy(nil)
 	if err != nil {
 		t.Errorf("Relationships.RequestedBy returned error: %v", err)
 	}
@@ -112,6 +113,94 @@ func TestRelationshipsService_RequestedBy(t *testing.T) {
 	}
 }
 
+func TestRelationshipsService_Follows_params(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/1/follows", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{
+			"count":  "2",
+			"cursor": "c1",
+		})
+		fmt.Fprint(w, `{"data": [{"id":"2"}], "pagination": {"next_cursor":"c2"}}`)
+	})
+
+	users, page, err := client.Relationships.Follows("1", &Parameters{Count: 2, Cursor: "c1"})
+	if err != nil {
+		t.Errorf("Relationships.Follows returned error: %v", err)
+	}
+
+	if want := []User{User{ID: "2"}}; !reflect.DeepEqual(users, want) {
+		t.Errorf("Relationships.Follows returned %+v, want %+v", users, want)
+	}
+	want := &ResponsePagination{NextCursor: "c2"}
+	if !reflect.DeepEqual(page, want) {
+		t.Errorf("Relationships.Follows returned pagination %+v, want %+v", page, want)
+	}
+}
+
+func TestRelationshipsService_FollowedBy_params(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/self/followed-by", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{
+			"count":  "",
+			"cursor": "c1",
+		})
+		fmt.Fprint(w, `{"data": [{"id":"2"}]}`)
+	})
+
+	_, _, err := client.Relationships.FollowedByContext(context.Background(), "", &RelationshipListOptions{Cursor: "c1"})
+	if err != nil {
+		t.Errorf("Relationships.FollowedByContext returned error: %v", err)
+	}
+}
+
+func TestRelationshipsService_RequestedBy_invalidCount(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, _, err := client.Relationships.RequestedByContext(context.Background(), &RelationshipListOptions{Count: -1})
+	if _, ok := err.(*OptionError); !ok {
+		t.Errorf("Relationships.RequestedByContext returned error %v, want an *OptionError", err)
+	}
+}
+
+func TestRelationshipsService_FollowedByIterator_cursor(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/1/followed-by", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if r.FormValue("count") != "1" {
+			t.Errorf("Request parameter count = %v, want 1", r.FormValue("count"))
+		}
+		switch r.FormValue("cursor") {
+		case "":
+			fmt.Fprint(w, `{"data": [{"id":"2"}], "pagination": {"next_cursor":"c1"}}`)
+		case "c1":
+			fmt.Fprint(w, `{"data": [{"id":"3"}], "pagination": {"next_cursor":"c2"}}`)
+		default:
+			fmt.Fprint(w, `{"data": [{"id":"4"}]}`)
+		}
+	})
+
+	it := client.Relationships.FollowedByIterator(context.Background(), "1", &RelationshipListOptions{Count: 1})
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Value().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Iterator returned error: %v", err)
+	}
+	if want := []string{"2", "3", "4"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Iterator returned %v, want %v", ids, want)
+	}
+}
+
 func TestRelationshipsService_Relationship(t *testing.T) {
 	setup()
 	defer teardown()
@@ -0,0 +1,164 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy specifies how Client.Do retries requests that failed with a
+// network error or a transient HTTP status.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+
+	// MinBackoff is the delay before the first retry. It doubles with every
+	// following retry, up to MaxBackoff if it's positive.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// Jitter randomly shortens each delay by up to this fraction (0 to 1),
+	// so that many clients failing at once don't retry in lockstep.
+	Jitter float64
+
+	// RetryableStatus lists the HTTP status codes worth retrying. When nil,
+	// 429 and 500, 502, 503, 504 are retried.
+	RetryableStatus []int
+
+	// Retryable, if set, replaces the default decision of whether an attempt
+	// should be retried. Exactly one of resp and err is non-nil.
+	Retryable func(resp *http.Response, err error) bool
+
+	// RetryNonIdempotent allows retrying POST requests. It's off by default
+	// because a POST that timed out may have been applied already, and
+	// retrying it would, for example, post a comment twice.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy is a reasonable RetryPolicy for most applications.
+var DefaultRetryPolicy = &RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  500 * time.Millisecond,
+	MaxBackoff:  10 * time.Second,
+	Jitter:      0.2,
+}
+
+var defaultRetryableStatus = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// retryable reports whether an attempt that ended with resp or err should be
+// retried.
+func (p *RetryPolicy) retryable(resp *http.Response, err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(resp, err)
+	}
+	if err != nil {
+		// Rate limit and context errors are final.
+		if _, ok := err.(*RatelimitError); ok {
+			return false
+		}
+		return true
+	}
+
+	codes := p.RetryableStatus
+	if codes == nil {
+		codes = defaultRetryableStatus
+	}
+	for _, code := range codes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay before retry number n, starting at 1.
+func (p *RetryPolicy) backoff(n int, resp *http.Response) time.Duration {
+	d := p.MinBackoff
+	for i := 1; i < n && (p.MaxBackoff <= 0 || d < p.MaxBackoff) && d <= math.MaxInt64/2; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(rand.Float64() * p.Jitter * float64(d))
+	}
+
+	// Respect the server's wish, as long as it stays within MaxBackoff.
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			if after := time.Duration(secs) * time.Second; after > d && (p.MaxBackoff == 0 || after <= p.MaxBackoff) {
+				d = after
+			}
+		}
+	}
+	return d
+}
+
+// idempotent reports whether req may be sent more than once.
+func (p *RetryPolicy) idempotent(req *http.Request) bool {
+	if req.Method == "POST" {
+		return p.RetryNonIdempotent
+	}
+	return true
+}
+
+// send sends req, retrying it according to the Client's RetryPolicy.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	p := c.RetryPolicy
+	if p == nil || p.MaxAttempts < 2 || !p.idempotent(req) {
+		return c.sendOnce(req)
+	}
+
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := c.sendOnce(req)
+		if ctx.Err() != nil || attempt >= p.MaxAttempts || !p.retryable(resp, err) {
+			return resp, err
+		}
+
+		// The body of a retried request has to be rewound; requests built
+		// by NewRequest always support that.
+		if req.Body != nil && req.GetBody == nil {
+			return resp, err
+		}
+
+		wait := p.backoff(attempt, resp)
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
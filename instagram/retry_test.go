@@ -0,0 +1,213 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testRetryPolicy = &RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  time.Millisecond,
+	MaxBackoff:  5 * time.Millisecond,
+}
+
+// failFirst registers a handler on path that replies with status to the first
+// n requests and succeeds afterwards. It returns a pointer to the number of
+// requests received.
+func failFirst(path string, n, status int) *int {
+	calls := 0
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= n {
+			w.WriteHeader(status)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"id":"1"}}`)
+	})
+	return &calls
+}
+
+func TestDo_retryStatus(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := failFirst("/media/1", 2, http.StatusServiceUnavailable)
+	client.RetryPolicy = testRetryPolicy
+
+	media, err := client.Media.Get("1")
+	if err != nil {
+		t.Fatalf("Media.Get returned error: %v", err)
+	}
+	if want := (&Media{ID: "1"}); !reflect.DeepEqual(media, want) {
+		t.Errorf("Media.Get returned %+v, want %+v", media, want)
+	}
+	if *calls != 3 {
+		t.Errorf("Server received %d requests, want 3", *calls)
+	}
+}
+
+func TestDo_retryExhausted(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := failFirst("/media/1", 5, http.StatusTooManyRequests)
+	client.RetryPolicy = testRetryPolicy
+
+	_, err := client.Media.Get("1")
+	if err == nil {
+		t.Errorf("Media.Get expected error to be returned")
+	}
+	if *calls != 3 {
+		t.Errorf("Server received %d requests, want 3", *calls)
+	}
+}
+
+func TestDo_retryNotRetryable(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := failFirst("/media/1", 1, http.StatusBadRequest)
+	client.RetryPolicy = testRetryPolicy
+
+	if _, err := client.Media.Get("1"); err == nil {
+		t.Errorf("Media.Get expected error to be returned")
+	}
+	if *calls != 1 {
+		t.Errorf("Server received %d requests, want 1", *calls)
+	}
+}
+
+func TestDo_retryNetworkError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// Hijack and close the connection of the first request to simulate a
+	// network failure.
+	calls := 0
+	mux.HandleFunc("/media/1", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		fmt.Fprint(w, `{"data":{"id":"1"}}`)
+	})
+	client.RetryPolicy = testRetryPolicy
+
+	if _, err := client.Media.Get("1"); err != nil {
+		t.Errorf("Media.Get returned error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Server received %d requests, want 2", calls)
+	}
+}
+
+func TestDo_retryPOSTNotRetried(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := failFirst("/media/1/comments", 1, http.StatusServiceUnavailable)
+	client.RetryPolicy = testRetryPolicy
+
+	if err := client.Comments.Add("1", []string{"hi"}); err == nil {
+		t.Errorf("Comments.Add expected error to be returned")
+	}
+	if *calls != 1 {
+		t.Errorf("Server received %d requests, want 1", *calls)
+	}
+}
+
+func TestDo_retryPOSTRewindsBody(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/media/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+		if want := "text=hi"; string(body) != want {
+			t.Errorf("Request body = %q, want %q", body, want)
+		}
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `{"meta":{"code":200}}`)
+	})
+
+	p := *testRetryPolicy
+	p.RetryNonIdempotent = true
+	client.RetryPolicy = &p
+
+	if err := client.Comments.Add("1", []string{"hi"}); err != nil {
+		t.Errorf("Comments.Add returned error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Server received %d requests, want 2", calls)
+	}
+}
+
+func TestDo_retryContextCanceled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	failFirst("/media/1", 5, http.StatusInternalServerError)
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.Media.GetContext(ctx, "1"); err != context.DeadlineExceeded {
+		t.Errorf("Media.GetContext returned error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	p := &RetryPolicy{MinBackoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	for n, want := range []time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 5: 5 * time.Second} {
+		if n == 0 {
+			continue
+		}
+		if got := p.backoff(n, nil); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", n, got, want)
+		}
+	}
+
+	// Without MaxBackoff, the delay keeps doubling.
+	uncapped := &RetryPolicy{MinBackoff: time.Second}
+	for n, want := range []time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 8 * time.Second} {
+		if n == 0 {
+			continue
+		}
+		if got := uncapped.backoff(n, nil); got != want {
+			t.Errorf("backoff(%d) without MaxBackoff = %v, want %v", n, got, want)
+		}
+	}
+	if got := uncapped.backoff(100, nil); got <= 0 {
+		t.Errorf("backoff(100) without MaxBackoff = %v, want it not to overflow", got)
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := p.backoff(2, nil); got < time.Second || got > 2*time.Second {
+			t.Errorf("backoff(2) with jitter = %v, want between 1s and 2s", got)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": {"3"}}}
+	if got, want := p.backoff(1, resp), 3*time.Second; got != want {
+		t.Errorf("backoff(1) with Retry-After = %v, want %v", got, want)
+	}
+}
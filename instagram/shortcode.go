@@ -0,0 +1,105 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"fmt"
+	"math/bits"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// shortcodeAlphabet is the URL-safe base64 alphabet shortcodes are written in.
+const shortcodeAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+// ParseShortcode extracts the shortcode from a media permalink such as
+// Media.Link, e.g. "BAR" from "http://instagram.com/p/BAR/". A bare
+// shortcode is returned as is.
+func ParseShortcode(link string) (string, error) {
+	if !strings.Contains(link, "/") {
+		if !validShortcode(link) {
+			return "", fmt.Errorf("instagram: invalid shortcode %q", link)
+		}
+		return link, nil
+	}
+
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	switch strings.TrimPrefix(strings.ToLower(u.Host), "www.") {
+	case "instagram.com", "instagr.am":
+	default:
+		return "", fmt.Errorf("instagram: %q is not an Instagram link", link)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || (parts[0] != "p" && parts[0] != "tv" && parts[0] != "reel") || !validShortcode(parts[1]) {
+		return "", fmt.Errorf("instagram: %q is not a media link", link)
+	}
+	return parts[1], nil
+}
+
+// ShortcodeToID converts a shortcode to the numeric part of the media ID,
+// i.e. the part before the underscore. The user ID suffix can't be derived
+// from the shortcode; use MediaService.Shortcode to get the full Media.
+func ShortcodeToID(code string) (string, error) {
+	if !validShortcode(code) {
+		return "", fmt.Errorf("instagram: invalid shortcode %q", code)
+	}
+
+	var id uint64
+	for _, r := range code {
+		hi, lo := bits.Mul64(id, 64)
+		if hi != 0 {
+			return "", fmt.Errorf("instagram: shortcode %q doesn't map to a media ID", code)
+		}
+		var carry uint64
+		id, carry = bits.Add64(lo, uint64(strings.IndexRune(shortcodeAlphabet, r)), 0)
+		if carry != 0 {
+			return "", fmt.Errorf("instagram: shortcode %q doesn't map to a media ID", code)
+		}
+	}
+	return strconv.FormatUint(id, 10), nil
+}
+
+// IDToShortcode converts a media ID, with or without its "_<user ID>"
+// suffix, to its shortcode.
+func IDToShortcode(id string) (string, error) {
+	if i := strings.Index(id, "_"); i >= 0 {
+		id = id[:i]
+	}
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("instagram: invalid media ID %q", id)
+	}
+
+	var code []byte
+	for {
+		code = append([]byte{shortcodeAlphabet[n%64]}, code...)
+		n /= 64
+		if n == 0 {
+			break
+		}
+	}
+	return string(code), nil
+}
+
+func validShortcode(code string) bool {
+	if code == "" {
+		return false
+	}
+	for _, r := range code {
+		if !strings.ContainsRune(shortcodeAlphabet, r) {
+			return false
+		}
+	}
+	return true
+}
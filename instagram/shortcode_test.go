@@ -0,0 +1,67 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"testing"
+)
+
+func TestParseShortcode(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{"http://instagram.com/p/BAR/", "BAR"},
+		{"https://www.instagram.com/p/Bf-3k_-hN1c/?taken-by=someone", "Bf-3k_-hN1c"},
+		{"instagram.com/p/D", "D"},
+		{"http://instagr.am/p/BAR", "BAR"},
+		{"https://www.instagram.com/tv/B1a2C3d4E5f/", "B1a2C3d4E5f"},
+		{"BAR", "BAR"},
+	}
+	for _, tt := range tests {
+		got, err := ParseShortcode(tt.link)
+		if err != nil {
+			t.Errorf("ParseShortcode(%q) returned error: %v", tt.link, err)
+		}
+		if got != tt.want {
+			t.Errorf("ParseShortcode(%q) = %q, want %q", tt.link, got, tt.want)
+		}
+	}
+
+	for _, link := range []string{"", "http://example.com/p/BAR/", "http://instagram.com/gedex/", "http://instagram.com/p/", "B@R"} {
+		if _, err := ParseShortcode(link); err == nil {
+			t.Errorf("ParseShortcode(%q) expected error to be returned", link)
+		}
+	}
+}
+
+func TestShortcodeConversion(t *testing.T) {
+	tests := []struct {
+		id   string
+		code string
+	}{
+		{"0", "A"},
+		{"3", "D"},
+		{"936303077400215759", "z-arAqi4DP"},
+	}
+	for _, tt := range tests {
+		code, err := IDToShortcode(tt.id + "_25025320")
+		if err != nil || code != tt.code {
+			t.Errorf("IDToShortcode(%q) = %q, %v, want %q", tt.id, code, err, tt.code)
+		}
+		id, err := ShortcodeToID(tt.code)
+		if err != nil || id != tt.id {
+			t.Errorf("ShortcodeToID(%q) = %q, %v, want %q", tt.code, id, err, tt.id)
+		}
+	}
+
+	if _, err := ShortcodeToID("BAR_that_is_way_too_long"); err == nil {
+		t.Errorf("ShortcodeToID expected error for an overflowing shortcode")
+	}
+	if _, err := IDToShortcode("abc"); err == nil {
+		t.Errorf("IDToShortcode expected error for a non-numeric ID")
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// GenerateSig returns the sig parameter of a signed request: the hex encoded
+// HMAC-SHA256, keyed with the client secret, of the endpoint path followed
+// by every parameter sorted by name, separated by "|". For example, the
+// string signed for GET /media/657988443280050001_25025320 is
+//
+//	/media/657988443280050001_25025320|access_token=fb2e77d.47a0479900504cb3ab4a1f626d174d2d|count=10
+//
+// Instagram API docs: http://instagram.com/developer/secure-api-requests/
+func GenerateSig(endpoint string, params url.Values, secret string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := []string{endpoint}
+	for _, k := range keys {
+		for _, v := range params[k] {
+			parts = append(parts, k+"="+v)
+		}
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// endpoint returns the path of u relative to the API root, e.g.
+// "/users/self", which is what request signatures are computed over.
+func (c *Client) endpoint(u *url.URL) string {
+	p := strings.TrimPrefix(u.Path, strings.TrimSuffix(c.BaseURL.Path, "/"))
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return p
+}
+
+// mergeValues returns the union of the given values.
+func mergeValues(vs ...url.Values) url.Values {
+	merged := url.Values{}
+	for _, v := range vs {
+		for key, values := range v {
+			merged[key] = append(merged[key], values...)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,104 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestGenerateSig(t *testing.T) {
+	// Example from http://instagram.com/developer/secure-api-requests/
+	params := url.Values{
+		"access_token": {"fb2e77d.47a0479900504cb3ab4a1f626d174d2d"},
+		"count":        {"10"},
+	}
+	sig := GenerateSig("/media/657988443280050001_25025320", params, "6dc1787668c64c939929c17683d7cb74")
+
+	want := "260634b241a6cfef5e4644c205fb30246ff637591142781b86e2075faf1b163a"
+	if sig != want {
+		t.Errorf("GenerateSig returned %v, want %v", sig, want)
+	}
+}
+
+func TestNewRequest_signed(t *testing.T) {
+	c := NewClient(nil)
+	c.AccessToken = "token"
+	c.ClientSecret = "secret"
+	c.EnforceSignedRequests = true
+
+	req, _ := c.NewRequest("GET", "media/1?count=10", "")
+	q := req.URL.Query()
+
+	if q.Get("client_secret") != "" {
+		t.Errorf("Signed request leaks client_secret: %v", req.URL)
+	}
+	want := GenerateSig("/media/1", url.Values{"access_token": {"token"}, "count": {"10"}}, "secret")
+	if got := q.Get("sig"); got != want {
+		t.Errorf("Signed request sig = %v, want %v", got, want)
+	}
+}
+
+func TestNewRequest_signedNextURL(t *testing.T) {
+	c := NewClient(nil)
+	c.ClientSecret = "secret"
+	c.EnforceSignedRequests = true
+
+	req, _ := c.NewRequest("GET", "https://api.instagram.com/v1/tags/t/media/recent?access_token=token&max_tag_id=2&sig=stale", "")
+	q := req.URL.Query()
+
+	if q.Get("client_secret") != "" {
+		t.Errorf("Signed request leaks client_secret: %v", req.URL)
+	}
+	want := GenerateSig("/tags/t/media/recent", url.Values{"access_token": {"token"}, "max_tag_id": {"2"}}, "secret")
+	if got := q.Get("sig"); got != want {
+		t.Errorf("Signed request sig = %v, want %v", got, want)
+	}
+}
+
+func TestLikesService_Like_signed(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.AccessToken = "token"
+	client.ClientSecret = "secret"
+	client.EnforceSignedRequests = true
+
+	mux.HandleFunc("/media/1/likes", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		want := GenerateSig("/media/1/likes", url.Values{"access_token": {"token"}}, "secret")
+		testFormValues(t, r, values{"sig": want, "client_secret": ""})
+		fmt.Fprint(w, `{"meta":{"code":200}}`)
+	})
+
+	if err := client.Likes.Like("1"); err != nil {
+		t.Errorf("Likes.Like returned error: %v", err)
+	}
+}
+
+func TestRelationshipsService_Follow_signed(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.AccessToken = "token"
+	client.ClientSecret = "secret"
+	client.EnforceSignedRequests = true
+
+	mux.HandleFunc("/users/1/relationship", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		want := GenerateSig("/users/1/relationship", url.Values{"access_token": {"token"}, "action": {"follow"}}, "secret")
+		if got := r.URL.Query().Get("sig"); got != want {
+			t.Errorf("Request sig = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `{"data":{"outgoing_status":"follows"}}`)
+	})
+
+	if _, err := client.Relationships.Follow("1"); err != nil {
+		t.Errorf("Relationships.Follow returned error: %v", err)
+	}
+}
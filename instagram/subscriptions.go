@@ -0,0 +1,185 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"context"
+	"net/url"
+)
+
+// SubscriptionsService handles communication with the real-time
+// subscriptions related methods of the Instagram API. These methods
+// authenticate with the Client's ClientID and ClientSecret; the secret is
+// sent even when the Client enforces signed requests, as the endpoint
+// requires it.
+//
+// Instagram API docs: http://instagram.com/developer/realtime/
+type SubscriptionsService struct {
+	client *Client
+}
+
+// Objects that can be subscribed to.
+const (
+	SubscriptionObjectUser      = "user"
+	SubscriptionObjectTag       = "tag"
+	SubscriptionObjectLocation  = "location"
+	SubscriptionObjectGeography = "geography"
+
+	// SubscriptionObjectAll can be passed to DeleteByObject to delete all
+	// of the application's subscriptions.
+	SubscriptionObjectAll = "all"
+)
+
+// SubscriptionAspectMedia is the only aspect currently supported.
+const SubscriptionAspectMedia = "media"
+
+// Subscription represents a real-time subscription.
+type Subscription struct {
+	ID          string `json:"id,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Object      string `json:"object,omitempty"`
+	ObjectID    string `json:"object_id,omitempty"`
+	Aspect      string `json:"aspect,omitempty"`
+	CallbackURL string `json:"callback_url,omitempty"`
+}
+
+// SubscriptionRequest specifies the subscription to create.
+type SubscriptionRequest struct {
+	// Object is one of the SubscriptionObject* values.
+	Object string `url:"object"`
+
+	// ObjectID is the tag name or location ID to subscribe to. It's not
+	// used for user and geography subscriptions.
+	ObjectID string `url:"object_id,omitempty"`
+
+	// Aspect defaults to SubscriptionAspectMedia.
+	Aspect string `url:"aspect"`
+
+	// CallbackURL receives the updates. Instagram verifies it with a GET
+	// request carrying VerifyToken before the subscription is created.
+	CallbackURL string `url:"callback_url"`
+	VerifyToken string `url:"verify_token,omitempty"`
+
+	// Center and radius, in meters up to 5000, of a geography subscription.
+	// They're only sent, zero or not, for geography subscriptions.
+	Lat    float64 `url:"-"`
+	Lng    float64 `url:"-"`
+	Radius float64 `url:"-"`
+}
+
+// subscriptionQuery holds the parameters of SubscriptionsService.CreateContext.
+type subscriptionQuery struct {
+	*SubscriptionRequest
+	*geographyQuery
+}
+
+type geographyQuery struct {
+	Lat    float64 `url:"lat,precision=7"`
+	Lng    float64 `url:"lng,precision=7"`
+	Radius float64 `url:"radius"`
+}
+
+// Create a subscription. For geography subscriptions, the returned
+// Subscription's ObjectID is the geography ID to be passed to
+// GeographiesService.RecentMedia.
+//
+// Instagram API docs: http://instagram.com/developer/realtime/#create-a-subscription
+func (s *SubscriptionsService) Create(sub *SubscriptionRequest) (*Subscription, error) {
+	return s.CreateContext(context.Background(), sub)
+}
+
+// CreateContext is like Create but takes a context that controls the request.
+func (s *SubscriptionsService) CreateContext(ctx context.Context, sub *SubscriptionRequest) (*Subscription, error) {
+	r := *sub
+	if r.Aspect == "" {
+		r.Aspect = SubscriptionAspectMedia
+	}
+	q := &subscriptionQuery{SubscriptionRequest: &r}
+	if r.Object == SubscriptionObjectGeography {
+		q.geographyQuery = &geographyQuery{r.Lat, r.Lng, r.Radius}
+	}
+	params, err := encodeQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	params = mergeValues(s.credentials(), params)
+
+	req, err := s.client.NewRequestContext(ctx, "POST", "subscriptions", params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	subscription := new(Subscription)
+	_, err = s.client.Do(req, subscription)
+	return subscription, err
+}
+
+// List the application's subscriptions.
+//
+// Instagram API docs: http://instagram.com/developer/realtime/#list-your-subscriptions
+func (s *SubscriptionsService) List() ([]Subscription, error) {
+	return s.ListContext(context.Background())
+}
+
+// ListContext is like List but takes a context that controls the request.
+func (s *SubscriptionsService) ListContext(ctx context.Context) ([]Subscription, error) {
+	u := "subscriptions?" + s.credentials().Encode()
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := new([]Subscription)
+	_, err = s.client.Do(req, subscriptions)
+	return *subscriptions, err
+}
+
+// Delete a subscription by its ID.
+//
+// Instagram API docs: http://instagram.com/developer/realtime/#delete-subscriptions
+func (s *SubscriptionsService) Delete(id string) error {
+	return s.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is like Delete but takes a context that controls the request.
+func (s *SubscriptionsService) DeleteContext(ctx context.Context, id string) error {
+	return deleteSubscriptions(ctx, s, "id", id)
+}
+
+// DeleteByObject deletes all subscriptions for an object type, e.g.
+// SubscriptionObjectTag, or every subscription with SubscriptionObjectAll.
+//
+// Instagram API docs: http://instagram.com/developer/realtime/#delete-subscriptions
+func (s *SubscriptionsService) DeleteByObject(object string) error {
+	return s.DeleteByObjectContext(context.Background(), object)
+}
+
+// DeleteByObjectContext is like DeleteByObject but takes a context that controls the request.
+func (s *SubscriptionsService) DeleteByObjectContext(ctx context.Context, object string) error {
+	return deleteSubscriptions(ctx, s, "object", object)
+}
+
+func deleteSubscriptions(ctx context.Context, s *SubscriptionsService, key, value string) error {
+	params := s.credentials()
+	params.Add(key, value)
+	u := "subscriptions?" + params.Encode()
+	req, err := s.client.NewRequestContext(ctx, "DELETE", u, "")
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(req, nil)
+	return err
+}
+
+// credentials returns the parameters authenticating subscription requests.
+func (s *SubscriptionsService) credentials() url.Values {
+	params := url.Values{}
+	if s.client.ClientSecret != "" {
+		params.Add("client_secret", s.client.ClientSecret)
+	}
+	return params
+}
@@ -0,0 +1,167 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSubscriptionsService_Create_tag(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testFormValues(t, r, values{
+			"object":       "tag",
+			"object_id":    "nofilter",
+			"aspect":       "media",
+			"callback_url": "http://example.com/cb",
+			"verify_token": "v",
+			"lat":          "",
+			"radius":       "",
+		})
+		fmt.Fprint(w, `{"data":{"id":"1","object":"tag","object_id":"nofilter","aspect":"media","callback_url":"http://example.com/cb","type":"subscription"}}`)
+	})
+
+	sub, err := client.Subscriptions.Create(&SubscriptionRequest{
+		Object:      SubscriptionObjectTag,
+		ObjectID:    "nofilter",
+		CallbackURL: "http://example.com/cb",
+		VerifyToken: "v",
+		Lat:         1,
+		Radius:      1000,
+	})
+	if err != nil {
+		t.Errorf("Subscriptions.Create returned error: %v", err)
+	}
+
+	want := &Subscription{
+		ID:          "1",
+		Type:        "subscription",
+		Object:      "tag",
+		ObjectID:    "nofilter",
+		Aspect:      "media",
+		CallbackURL: "http://example.com/cb",
+	}
+	if !reflect.DeepEqual(sub, want) {
+		t.Errorf("Subscriptions.Create returned %+v, want %+v", sub, want)
+	}
+}
+
+func TestSubscriptionsService_Create_geography(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testFormValues(t, r, values{
+			"object":    "geography",
+			"object_id": "",
+			"lat":       "35.6579600",
+			"lng":       "139.6996700",
+			"radius":    "1000",
+		})
+		fmt.Fprint(w, `{"data":{"id":"2","object":"geography","object_id":"42"}}`)
+	})
+
+	sub, err := client.Subscriptions.Create(&SubscriptionRequest{
+		Object:      SubscriptionObjectGeography,
+		CallbackURL: "http://example.com/cb",
+		Lat:         35.65796,
+		Lng:         139.69967,
+		Radius:      1000,
+	})
+	if err != nil {
+		t.Errorf("Subscriptions.Create returned error: %v", err)
+	}
+
+	want := &Subscription{ID: "2", Object: "geography", ObjectID: "42"}
+	if !reflect.DeepEqual(sub, want) {
+		t.Errorf("Subscriptions.Create returned %+v, want %+v", sub, want)
+	}
+}
+
+func TestSubscriptionsService_Create_geographyZero(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		for _, key := range []string{"lat", "lng", "radius"} {
+			if _, ok := r.PostForm[key]; !ok {
+				t.Errorf("Request parameter %v missing", key)
+			}
+		}
+		testFormValues(t, r, values{"lat": "0.0000000", "lng": "0.0000000", "radius": "0"})
+		fmt.Fprint(w, `{"data":{"id":"3","object":"geography"}}`)
+	})
+
+	_, err := client.Subscriptions.Create(&SubscriptionRequest{
+		Object:      SubscriptionObjectGeography,
+		CallbackURL: "http://example.com/cb",
+	})
+	if err != nil {
+		t.Errorf("Subscriptions.Create returned error: %v", err)
+	}
+}
+
+func TestSubscriptionsService_List(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.ClientSecret = "secret"
+	client.EnforceSignedRequests = true
+
+	mux.HandleFunc("/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{"client_secret": "secret"})
+		fmt.Fprint(w, `{"data":[{"id":"1"},{"id":"2"}]}`)
+	})
+
+	subs, err := client.Subscriptions.List()
+	if err != nil {
+		t.Errorf("Subscriptions.List returned error: %v", err)
+	}
+
+	want := []Subscription{{ID: "1"}, {ID: "2"}}
+	if !reflect.DeepEqual(subs, want) {
+		t.Errorf("Subscriptions.List returned %+v, want %+v", subs, want)
+	}
+}
+
+func TestSubscriptionsService_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		testFormValues(t, r, values{"id": "1"})
+		fmt.Fprint(w, `{"meta":{"code":200}}`)
+	})
+
+	if err := client.Subscriptions.Delete("1"); err != nil {
+		t.Errorf("Subscriptions.Delete returned error: %v", err)
+	}
+}
+
+func TestSubscriptionsService_DeleteByObject(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		testFormValues(t, r, values{"object": "all"})
+		fmt.Fprint(w, `{"meta":{"code":200}}`)
+	})
+
+	if err := client.Subscriptions.DeleteByObject(SubscriptionObjectAll); err != nil {
+		t.Errorf("Subscriptions.DeleteByObject returned error: %v", err)
+	}
+}
@@ -6,6 +6,7 @@
 package instagram
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 )
@@ -24,12 +25,45 @@ type Tag struct {
 	Name       string `json:"name,omitempty"`
 }
 
+// TagRecentMediaOptions specifies the optional parameters to
+// TagsService.RecentMediaContext.
+type TagRecentMediaOptions struct {
+	// Count of tagged media to return.
+	Count int `url:"count,omitempty"`
+
+	// Return media later than MinID and earlier than MaxID.
+	MinID string `url:"min_id,omitempty"`
+	MaxID string `url:"max_id,omitempty"`
+
+	// Return media tagged after MinTagID and before MaxTagID. These are the
+	// min_tag_id and next_max_tag_id of a previous page's pagination.
+	MinTagID string `url:"min_tag_id,omitempty"`
+	MaxTagID string `url:"max_tag_id,omitempty"`
+
+	legacyOptions
+}
+
+// tagSearchQuery holds the parameters of TagsService.SearchContext.
+type tagSearchQuery struct {
+	Q string `url:"q"`
+}
+
+// Validate returns an error if any option is out of range.
+func (o *TagRecentMediaOptions) Validate() error {
+	return validateCount(o.Count)
+}
+
 // Get information aout a tag object.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/tags/#get_tags
 func (s *TagsService) Get(tagName string) (*Tag, error) {
-	u := fmt.Sprintf("tags/%v", tagName)
-	req, err := s.client.NewRequest("GET", u, "")
+	return s.GetContext(context.Background(), tagName)
+}
+
+// GetContext is like Get but takes a context that controls the request.
+func (s *TagsService) GetContext(ctx context.Context, tagName string) (*Tag, error) {
+	u := fmt.Sprintf("tags/%v", url.PathEscape(tagName))
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
 	if err != nil {
 		return nil, err
 	}
@@ -43,57 +77,79 @@ func (s *TagsService) Get(tagName string) (*Tag, error) {
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/tags/#get_tags_media_recent
 func (s *TagsService) RecentMedia(tagName string, opt *Parameters) ([]Media, *ResponsePagination, error) {
-	u := fmt.Sprintf("tags/%v/media/recent", tagName)
-	if opt != nil {
-		params := url.Values{}
-		if opt.MinID != "" {
-			params.Add("min_id", opt.MinID)
-		}
-		if opt.MaxID != "" {
-			params.Add("max_id", opt.MaxID)
+	return s.RecentMediaContext(context.Background(), tagName, opt.tagRecentMediaOptions())
+}
+
+// RecentMediaContext is like RecentMedia but takes a context that controls
+// the request, and options specific to this endpoint.
+func (s *TagsService) RecentMediaContext(ctx context.Context, tagName string, opt *TagRecentMediaOptions) ([]Media, *ResponsePagination, error) {
+	u := fmt.Sprintf("tags/%v/media/recent", url.PathEscape(tagName))
+	if opt != nil && !opt.legacy {
+		if err := opt.Validate(); err != nil {
+			return nil, nil, err
 		}
-		u += "?" + params.Encode()
 	}
-	req, err := s.client.NewRequest("GET", u, "")
+	u, err := addOptions(u, opt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
 	if err != nil {
 		return nil, nil, err
 	}
 
 	media := new([]Media)
 
-	_, err = s.client.Do(req, media)
+	resp, err := s.client.Do(req, media)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	page := new(ResponsePagination)
-	if s.client.Response.Pagination != nil {
-		page = s.client.Response.Pagination
+	if resp.Pagination != nil {
+		page = resp.Pagination
 	}
 
 	return *media, page, err
 }
 
+// RecentMediaIterator returns an Iterator over all pages of the media tagged with tagName.
+func (s *TagsService) RecentMediaIterator(ctx context.Context, tagName string, opt *TagRecentMediaOptions) *Iterator[Media] {
+	return newIterator(ctx, s.client, func(ctx context.Context) ([]Media, *ResponsePagination, error) {
+		return s.RecentMediaContext(ctx, tagName, opt)
+	})
+}
+
 // Search for tags by name.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/tags/#get_tags_search
 func (s *TagsService) Search(q string) ([]Tag, *ResponsePagination, error) {
-	u := "tags/search?q=" + q
-	req, err := s.client.NewRequest("GET", u, "")
+	return s.SearchContext(context.Background(), q)
+}
+
+// SearchContext is like Search but takes a context that controls the request.
+func (s *TagsService) SearchContext(ctx context.Context, q string) ([]Tag, *ResponsePagination, error) {
+	u, err := addOptions("tags/search", &tagSearchQuery{q})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
 	if err != nil {
 		return nil, nil, err
 	}
 
 	tags := new([]Tag)
 
-	_, err = s.client.Do(req, tags)
+	resp, err := s.client.Do(req, tags)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	page := new(ResponsePagination)
-	if s.client.Response.Pagination != nil {
-		page = s.client.Response.Pagination
+	if resp.Pagination != nil {
+		page = resp.Pagination
 	}
 
 	return *tags, page, err
@@ -6,6 +6,7 @@
 package instagram
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -82,3 +83,62 @@ func TestTagsService_Search(t *testing.T) {
 		t.Errorf("Tags.Search returned %+v, want %+v", tags, want)
 	}
 }
+
+func TestTagsService_Search_escaping(t *testing.T) {
+	setup()
+	defer teardown()
+
+	q := "café & #go fun"
+	mux.HandleFunc("/tags/search", func(w http.ResponseWriter, r *http.Request) {
+		testFormValues(t, r, values{"q": q})
+		fmt.Fprint(w, `{"data": []}`)
+	})
+
+	if _, _, err := client.Tags.Search(q); err != nil {
+		t.Errorf("Tags.Search returned error: %v", err)
+	}
+}
+
+func TestTagsService_Get_escaping(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/tags/", func(w http.ResponseWriter, r *http.Request) {
+		if want := "/tags/caf%C3%A9%20&%20%23go"; r.URL.EscapedPath() != want {
+			t.Errorf("Request path = %v, want %v", r.URL.EscapedPath(), want)
+		}
+		fmt.Fprint(w, `{"data":{"name": "café & #go"}}`)
+	})
+
+	if _, err := client.Tags.Get("café & #go"); err != nil {
+		t.Errorf("Tags.Get returned error: %v", err)
+	}
+}
+
+func TestTagsService_RecentMediaContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/tags/tag-name/media/recent", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{
+			"count":  "5",
+			"max_id": "1",
+		})
+		fmt.Fprint(w, `{"data": [{"id":"1"}]}`)
+	})
+
+	opt := &TagRecentMediaOptions{
+		Count: 5,
+		MaxID: "1",
+	}
+	media, _, err := client.Tags.RecentMediaContext(context.Background(), "tag-name", opt)
+	if err != nil {
+		t.Errorf("Tags.RecentMediaContext returned error: %v", err)
+	}
+
+	want := []Media{Media{ID: "1"}}
+	if !reflect.DeepEqual(media, want) {
+		t.Errorf("Tags.RecentMediaContext returned %+v, want %+v", media, want)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var timestampType = reflect.TypeOf(Timestamp{})
+
+// Timestamp is a time.Time which the API exchanges as unix seconds. It
+// unmarshals from JSON strings, as Instagram sends them, or numbers, and
+// marshals back to a string of seconds. The zero Timestamp marshals to null.
+type Timestamp struct {
+	time.Time
+}
+
+// Unix returns the Timestamp corresponding to the given unix time, in UTC.
+// Zero seconds yield the zero Timestamp.
+func Unix(sec int64) Timestamp {
+	if sec == 0 {
+		return Timestamp{}
+	}
+	return Timestamp{time.Unix(sec, 0).UTC()}
+}
+
+// Equal reports whether t and u represent the same instant.
+func (t Timestamp) Equal(u Timestamp) bool {
+	return t.Time.Equal(u.Time)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(strconv.FormatInt(t.Unix(), 10))), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if s := string(data); s == "null" || s == "" {
+		*t = Timestamp{}
+		return nil
+	}
+	sec, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("instagram: invalid timestamp %q", data)
+	}
+	*t = Unix(sec)
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2013 The go-instagram AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimestamp_UnmarshalJSON(t *testing.T) {
+	want := time.Date(2013, 6, 25, 16, 0, 0, 0, time.UTC)
+	for _, data := range []string{`"1372176000"`, `1372176000`} {
+		var ts Timestamp
+		if err := json.Unmarshal([]byte(data), &ts); err != nil {
+			t.Errorf("Unmarshal(%s) returned error: %v", data, err)
+			continue
+		}
+		if !ts.Time.Equal(want) {
+			t.Errorf("Unmarshal(%s) = %v, want %v", data, ts, want)
+		}
+	}
+
+	for _, data := range []string{`null`, `""`} {
+		ts := Unix(1)
+		if err := json.Unmarshal([]byte(data), &ts); err != nil || !ts.IsZero() {
+			t.Errorf("Unmarshal(%s) = %v, %v, want zero Timestamp", data, ts, err)
+		}
+	}
+
+	var ts Timestamp
+	if err := json.Unmarshal([]byte(`"yesterday"`), &ts); err == nil {
+		t.Errorf("Unmarshal expected error to be returned")
+	}
+}
+
+func TestTimestamp_MarshalJSON(t *testing.T) {
+	c := &Comment{CreatedTime: Unix(1372176000)}
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+
+	got := new(Comment)
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if got.CreatedTime != c.CreatedTime {
+		t.Errorf("Round trip through %s gave %v, want %v", data, got.CreatedTime, c.CreatedTime)
+	}
+
+	if data, _ := json.Marshal(Timestamp{}); string(data) != "null" {
+		t.Errorf("Marshal(Timestamp{}) = %s, want null", data)
+	}
+}
+
+func TestMedia_MarshalJSON_createdTime(t *testing.T) {
+	m := &Media{
+		ID:          "1",
+		CreatedTime: Unix(1372176000),
+		Caption:     &MediaCaption{Text: "c"},
+		Comments:    &MediaComments{Data: []*Comment{{ID: "2", CreatedTime: Unix(1372176001)}}},
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+
+	got := new(Media)
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("Round trip through %s gave %+v, want %+v", data, got, m)
+	}
+
+	// Zero timestamps are left out rather than written as null.
+	if strings.Contains(string(data), "null") {
+		t.Errorf("Marshal wrote %s, want the zero caption created_time left out", data)
+	}
+	if data, _ := json.Marshal(&Media{ID: "1"}); string(data) != `{"id":"1"}` {
+		t.Errorf("Marshal(Media without created_time) = %s, want {\"id\":\"1\"}", data)
+	}
+}
+
+func TestMediaService_Get_createdTime(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/media/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"1","created_time":"1372176000","caption":{"created_time":"1372176001"}}}`)
+	})
+
+	media, err := client.Media.Get("1")
+	if err != nil {
+		t.Fatalf("Media.Get returned error: %v", err)
+	}
+	if want := Unix(1372176000); media.CreatedTime != want {
+		t.Errorf("Media.CreatedTime = %v, want %v", media.CreatedTime, want)
+	}
+	if want := Unix(1372176001); media.Caption == nil || media.Caption.CreatedTime != want {
+		t.Errorf("Media.Caption = %+v, want CreatedTime %v", media.Caption, want)
+	}
+}
+
+func TestUsersService_RecentMediaContext_timestamps(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/1/media/recent", func(w http.ResponseWriter, r *http.Request) {
+		testFormValues(t, r, values{
+			"min_timestamp": "1372176000",
+			"max_timestamp": "1372262400",
+		})
+		fmt.Fprint(w, `{"data":[]}`)
+	})
+
+	opt := &UserRecentMediaOptions{
+		MinTimestamp: Timestamp{time.Date(2013, 6, 25, 16, 0, 0, 0, time.UTC)},
+		MaxTimestamp: Timestamp{time.Date(2013, 6, 26, 16, 0, 0, 0, time.UTC)},
+	}
+	if _, _, err := client.Users.RecentMediaContext(context.Background(), "1", opt); err != nil {
+		t.Errorf("Users.RecentMediaContext returned error: %v", err)
+	}
+}
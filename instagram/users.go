@@ -6,9 +6,8 @@
 package instagram
 
 import (
+	"context"
 	"fmt"
-	"net/url"
-	"strconv"
 )
 
 // UsersService handles communication with the user related
@@ -37,18 +36,105 @@ type UserCount struct {
 	FollowedBy int `json:"followed_by,omitempty"`
 }
 
+// MediaFeedOptions specifies the optional parameters to
+// UsersService.MediaFeedContext.
+type MediaFeedOptions struct {
+	// Count of media to return.
+	Count int `url:"count,omitempty"`
+
+	// Return media later than MinID.
+	MinID string `url:"min_id,omitempty"`
+
+	// Return media earlier than MaxID.
+	MaxID string `url:"max_id,omitempty"`
+
+	legacyOptions
+}
+
+// Validate returns an error if any option is out of range.
+func (o *MediaFeedOptions) Validate() error {
+	return validateCount(o.Count)
+}
+
+// UserRecentMediaOptions specifies the optional parameters to
+// UsersService.RecentMediaContext.
+type UserRecentMediaOptions struct {
+	// Count of media to return.
+	Count int `url:"count,omitempty"`
+
+	// Return media after MinTimestamp and before MaxTimestamp.
+	MinTimestamp Timestamp `url:"min_timestamp,omitempty"`
+	MaxTimestamp Timestamp `url:"max_timestamp,omitempty"`
+
+	// Return media later than MinID and earlier than MaxID.
+	MinID string `url:"min_id,omitempty"`
+	MaxID string `url:"max_id,omitempty"`
+
+	legacyOptions
+}
+
+// Validate returns an error if any option is out of range.
+func (o *UserRecentMediaOptions) Validate() error {
+	if err := validateCount(o.Count); err != nil {
+		return err
+	}
+	return validateTimestamps(o.MinTimestamp, o.MaxTimestamp)
+}
+
+// LikedMediaOptions specifies the optional parameters to
+// UsersService.LikedMediaContext.
+type LikedMediaOptions struct {
+	// Count of media to return.
+	Count int `url:"count,omitempty"`
+
+	// Return media liked before this id.
+	MaxLikeID string `url:"max_like_id,omitempty"`
+
+	legacyOptions
+}
+
+// Validate returns an error if any option is out of range.
+func (o *LikedMediaOptions) Validate() error {
+	return validateCount(o.Count)
+}
+
+// UserSearchOptions specifies the optional parameters to
+// UsersService.SearchContext.
+type UserSearchOptions struct {
+	// Number of users to return.
+	Count int `url:"count,omitempty"`
+
+	legacyOptions
+}
+
+// userSearchQuery holds the parameters of UsersService.SearchContext.
+type userSearchQuery struct {
+	Q string `url:"q"`
+	*UserSearchOptions
+}
+
+// Validate returns an error if any option is out of range.
+func (o *UserSearchOptions) Validate() error {
+	return validateCount(o.Count)
+}
+
 // Get basic information about a user. Passing the empty string will fetch the authenticated
 // user.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/users/#get_users
 func (s *UsersService) Get(userId string) (*User, error) {
+	return s.GetContext(context.Background(), userId)
+}
+
+// GetContext is like Get but takes a context that controls the request.
+func (s *UsersService) GetContext(ctx context.Context, userId string) (*User, error) {
 	var u string
 	if userId != "" {
 		u = fmt.Sprintf("users/%v", userId)
 	} else {
 		u = "users/self"
 	}
-	req, err := s.client.NewRequest("GET", u, "")
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
 	if err != nil {
 		return nil, err
 	}
@@ -62,156 +148,186 @@ func (s *UsersService) Get(userId string) (*User, error) {
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/users/#get_users_feed
 func (s *UsersService) MediaFeed(opt *Parameters) ([]Media, *ResponsePagination, error) {
+	return s.MediaFeedContext(context.Background(), opt.mediaFeedOptions())
+}
+
+// MediaFeedContext is like MediaFeed but takes a context that controls the
+// request, and options specific to this endpoint.
+func (s *UsersService) MediaFeedContext(ctx context.Context, opt *MediaFeedOptions) ([]Media, *ResponsePagination, error) {
 	u := "users/self/feed"
-	if opt != nil {
-		params := url.Values{}
-		if opt.Count != 0 {
-			params.Add("count", strconv.FormatUint(opt.Count, 10))
+	if opt != nil && !opt.legacy {
+		if err := opt.Validate(); err != nil {
+			return nil, nil, err
 		}
-		if opt.MinID != "" {
-			params.Add("min_id", opt.MinID)
-		}
-		if opt.MaxID != "" {
-			params.Add("max_id", opt.MaxID)
-		}
-		u += "?" + params.Encode()
+	}
+	u, err := addOptions(u, opt)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", u, "")
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
 	if err != nil {
 		return nil, nil, err
 	}
 
 	media := new([]Media)
 
-	_, err = s.client.Do(req, media)
+	resp, err := s.client.Do(req, media)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	page := new(ResponsePagination)
-	if s.client.Response.Pagination != nil {
-		page = s.client.Response.Pagination
+	if resp.Pagination != nil {
+		page = resp.Pagination
 	}
 
 	return *media, page, err
 }
 
+// MediaFeedIterator returns an Iterator over all pages of the authenticated user's feed.
+func (s *UsersService) MediaFeedIterator(ctx context.Context, opt *MediaFeedOptions) *Iterator[Media] {
+	return newIterator(ctx, s.client, func(ctx context.Context) ([]Media, *ResponsePagination, error) {
+		return s.MediaFeedContext(ctx, opt)
+	})
+}
+
 // RecentMedia gets the most recent media published by a user.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/users/#get_users_media_recent
 func (s *UsersService) RecentMedia(userId string, opt *Parameters) ([]Media, *ResponsePagination, error) {
+	return s.RecentMediaContext(context.Background(), userId, opt.userRecentMediaOptions())
+}
+
+// RecentMediaContext is like RecentMedia but takes a context that controls
+// the request, and options specific to this endpoint.
+func (s *UsersService) RecentMediaContext(ctx context.Context, userId string, opt *UserRecentMediaOptions) ([]Media, *ResponsePagination, error) {
 	var u string
 	if userId != "" {
 		u = fmt.Sprintf("users/%v/media/recent", userId)
 	} else {
 		u = "users/self/media/recent"
 	}
-	if opt != nil {
-		params := url.Values{}
-		if opt.Count != 0 {
-			params.Add("count", strconv.FormatUint(opt.Count, 10))
-		}
-		if opt.MaxTimestamp != 0 {
-			params.Add("max_timestamp", strconv.FormatInt(opt.MaxTimestamp, 10))
-		}
-		if opt.MinTimestamp != 0 {
-			params.Add("min_timestamp", strconv.FormatInt(opt.MinTimestamp, 10))
-		}
-		if opt.MinID != "" {
-			params.Add("min_id", opt.MinID)
+	if opt != nil && !opt.legacy {
+		if err := opt.Validate(); err != nil {
+			return nil, nil, err
 		}
-		if opt.MaxID != "" {
-			params.Add("max_id", opt.MaxID)
-		}
-		u += "?" + params.Encode()
+	}
+	u, err := addOptions(u, opt)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", u, "")
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
 	if err != nil {
 		return nil, nil, err
 	}
 
 	media := new([]Media)
 
-	_, err = s.client.Do(req, media)
+	resp, err := s.client.Do(req, media)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	page := new(ResponsePagination)
-	if s.client.Response.Pagination != nil {
-		page = s.client.Response.Pagination
+	if resp.Pagination != nil {
+		page = resp.Pagination
 	}
 
 	return *media, page, err
 }
 
+// RecentMediaIterator returns an Iterator over all pages of the media published by a user.
+func (s *UsersService) RecentMediaIterator(ctx context.Context, userId string, opt *UserRecentMediaOptions) *Iterator[Media] {
+	return newIterator(ctx, s.client, func(ctx context.Context) ([]Media, *ResponsePagination, error) {
+		return s.RecentMediaContext(ctx, userId, opt)
+	})
+}
+
 // LikedMedia gets authenticated user's list of media they've liked.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/users/#get_users_feed_liked
 func (s *UsersService) LikedMedia(opt *Parameters) ([]Media, *ResponsePagination, error) {
+	return s.LikedMediaContext(context.Background(), opt.likedMediaOptions())
+}
+
+// LikedMediaContext is like LikedMedia but takes a context that controls the
+// request, and options specific to this endpoint.
+func (s *UsersService) LikedMediaContext(ctx context.Context, opt *LikedMediaOptions) ([]Media, *ResponsePagination, error) {
 	u := "users/self/media/liked"
-	if opt != nil {
-		params := url.Values{}
-		if opt.Count != 0 {
-			params.Add("count", strconv.FormatUint(opt.Count, 10))
+	if opt != nil && !opt.legacy {
+		if err := opt.Validate(); err != nil {
+			return nil, nil, err
 		}
-		if opt.MaxID != "" {
-			params.Add("max_like_id", opt.MaxID)
-		}
-		u += "?" + params.Encode()
+	}
+	u, err := addOptions(u, opt)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	req, err := s.client.NewRequest("GET", u, "")
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
 	if err != nil {
 		return nil, nil, err
 	}
 
 	media := new([]Media)
 
-	_, err = s.client.Do(req, media)
+	resp, err := s.client.Do(req, media)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	page := new(ResponsePagination)
-	if s.client.Response.Pagination != nil {
-		page = s.client.Response.Pagination
+	if resp.Pagination != nil {
+		page = resp.Pagination
 	}
 
 	return *media, page, err
 }
 
+// LikedMediaIterator returns an Iterator over all pages of the media liked by the authenticated user.
+func (s *UsersService) LikedMediaIterator(ctx context.Context, opt *LikedMediaOptions) *Iterator[Media] {
+	return newIterator(ctx, s.client, func(ctx context.Context) ([]Media, *ResponsePagination, error) {
+		return s.LikedMediaContext(ctx, opt)
+	})
+}
+
 // Search for a user by name.
 //
 // Instagram API docs: http://instagram.com/developer/endpoints/users/#get_users_search
 func (s *UsersService) Search(q string, opt *Parameters) ([]User, *ResponsePagination, error) {
-	u := "users/search"
-	params := url.Values{}
-	params.Add("q", q)
-	if opt != nil {
-		if opt.Count != 0 {
-			params.Add("count", strconv.FormatUint(opt.Count, 10))
+	return s.SearchContext(context.Background(), q, opt.userSearchOptions())
+}
+
+// SearchContext is like Search but takes a context that controls the
+// request, and options specific to this endpoint.
+func (s *UsersService) SearchContext(ctx context.Context, q string, opt *UserSearchOptions) ([]User, *ResponsePagination, error) {
+	if opt != nil && !opt.legacy {
+		if err := opt.Validate(); err != nil {
+			return nil, nil, err
 		}
 	}
-	u += "?" + params.Encode()
+	u, err := addOptions("users/search", &userSearchQuery{q, opt})
+	if err != nil {
+		return nil, nil, err
+	}
 
-	req, err := s.client.NewRequest("GET", u, "")
+	req, err := s.client.NewRequestContext(ctx, "GET", u, "")
 	if err != nil {
 		return nil, nil, err
 	}
 
 	users := new([]User)
 
-	_, err = s.client.Do(req, users)
+	resp, err := s.client.Do(req, users)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	page := new(ResponsePagination)
-	if s.client.Response.Pagination != nil {
-		page = s.client.Response.Pagination
+	if resp.Pagination != nil {
+		page = resp.Pagination
 	}
 
 	return *users, page, err
@@ -6,6 +6,7 @@
 package instagram
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -170,3 +171,20 @@ func TestUsersService_Search(t *testing.T) {
 		t.Errorf("Users.Search returned %+v, want %+v", users, want)
 	}
 }
+
+func TestUsersService_GetContext_canceled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/1", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Request sent with a canceled context")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.Users.GetContext(ctx, "1")
+	if err != context.Canceled {
+		t.Errorf("Users.GetContext returned error %v, want %v", err, context.Canceled)
+	}
+}